@@ -0,0 +1,152 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// Authenticator adds credentials to every request sent by
+// the client, see WithAuth.
+type Authenticator interface {
+	Authenticate(req *http.Request) error
+}
+
+// Refresher is implemented by an Authenticator whose
+// credentials can expire. When the server answers 401 the
+// client calls Refresh with the rejected request, as sent
+// with its credentials, and retries the request once.
+// Refresh may be called concurrently for several requests
+// rejected with the same credentials; it should only
+// replace credentials which are still those of rejected.
+type Refresher interface {
+	Refresh(ctx context.Context, rejected *http.Request) error
+}
+
+// WithAuth makes the client authenticate every request
+// with a.
+func WithAuth(a Authenticator) Option {
+	return func(o *options) error {
+		if a == nil {
+			return errors.New("api: nil authenticator")
+		}
+		o.auth = a
+		return nil
+	}
+}
+
+type basicAuth struct {
+	username string
+	password string
+}
+
+// BasicAuth returns an Authenticator which sends static
+// HTTP basic auth credentials.
+func BasicAuth(username, password string) Authenticator {
+	return basicAuth{username: username, password: password}
+}
+
+func (a basicAuth) Authenticate(req *http.Request) error {
+	req.SetBasicAuth(a.username, a.password)
+	return nil
+}
+
+type bearerToken string
+
+// BearerToken returns an Authenticator which sends a static
+// bearer token.
+func BearerToken(token string) Authenticator {
+	return bearerToken(token)
+}
+
+func (t bearerToken) Authenticate(req *http.Request) error {
+	req.Header.Set("Authorization", "Bearer "+string(t))
+	return nil
+}
+
+// TokenSource fetches bearer tokens, e.g. from an OAuth2
+// server. Token is called for the first request and again
+// whenever the server rejects the current token.
+type TokenSource interface {
+	Token(ctx context.Context) (string, error)
+}
+
+// TokenSourceFunc adapts a function to a TokenSource.
+type TokenSourceFunc func(ctx context.Context) (string, error)
+
+func (f TokenSourceFunc) Token(ctx context.Context) (string, error) {
+	return f(ctx)
+}
+
+type tokenSourceAuth struct {
+	source TokenSource
+
+	mu    sync.Mutex
+	token string
+
+	// fetching is closed when the running fetch of a token
+	// finished, or nil if no fetch is running.
+	fetching chan struct{}
+}
+
+// TokenSourceAuth returns an Authenticator which sends the
+// bearer token of ts. The token is cached until the server
+// answers 401, then a new one is fetched. Concurrent
+// requests share a single fetch.
+func TokenSourceAuth(ts TokenSource) Authenticator {
+	return &tokenSourceAuth{source: ts}
+}
+
+func (a *tokenSourceAuth) Authenticate(req *http.Request) error {
+	token, err := a.fetch(req.Context(), "")
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	return nil
+}
+
+func (a *tokenSourceAuth) Refresh(ctx context.Context, rejected *http.Request) error {
+	stale := strings.TrimPrefix(rejected.Header.Get("Authorization"), "Bearer ")
+	_, err := a.fetch(ctx, stale)
+	return err
+}
+
+// fetch returns the cached token unless it is empty or
+// stale, in which case a new token is fetched. The token
+// source is called without holding a.mu, and callers which
+// need a token while a fetch is running wait for it.
+func (a *tokenSourceAuth) fetch(ctx context.Context, stale string) (string, error) {
+	a.mu.Lock()
+	for a.token == "" || a.token == stale {
+		if a.fetching == nil {
+			done := make(chan struct{})
+			a.fetching = done
+			a.mu.Unlock()
+
+			token, err := a.source.Token(ctx)
+
+			a.mu.Lock()
+			if err == nil {
+				a.token = token
+			}
+			a.fetching = nil
+			a.mu.Unlock()
+			close(done)
+			return token, err
+		}
+		done := a.fetching
+		a.mu.Unlock()
+		select {
+		case <-done:
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+		a.mu.Lock()
+	}
+	token := a.token
+	a.mu.Unlock()
+	return token, nil
+}
@@ -0,0 +1,164 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// countingTokens returns the tokens "t1", "t2", ... and
+// counts the calls.
+type countingTokens struct {
+	calls int64
+}
+
+func (s *countingTokens) Token(ctx context.Context) (string, error) {
+	n := atomic.AddInt64(&s.calls, 1)
+	time.Sleep(10 * time.Millisecond)
+	return fmt.Sprintf("t%d", n), nil
+}
+
+// tokenServer rejects every token but want with 401.
+func tokenServer(want string, handle http.HandlerFunc) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+want {
+			io.Copy(io.Discard, r.Body)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		handle(w, r)
+	}))
+}
+
+func TestRefreshResendsMultipartUpload(t *testing.T) {
+	jar := []byte("PK\x03\x04 not really a jar")
+	path := filepath.Join(t.TempDir(), "job.jar")
+	if err := os.WriteFile(path, jar, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []byte
+	srv := tokenServer("t2", func(w http.ResponseWriter, r *http.Request) {
+		file, header, err := r.FormFile("jarfile")
+		if err != nil {
+			t.Errorf("read upload: %v", err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		defer file.Close()
+		if header.Filename != "job.jar" {
+			t.Errorf("file name = %q, want job.jar", header.Filename)
+		}
+		got, _ = io.ReadAll(file)
+		w.Write([]byte(`{"filename":"/tmp/abc_job.jar","status":"success"}`))
+	})
+	defer srv.Close()
+
+	tokens := &countingTokens{}
+	c, err := New(srv.URL, WithAuth(TokenSourceAuth(tokens)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var info CallInfo
+	r, err := c.UploadJarCtx(WithCallInfo(context.Background(), &info), path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Status != "success" {
+		t.Errorf("status = %q, want success", r.Status)
+	}
+	if !bytes.Equal(got, jar) {
+		t.Errorf("server received %q, want %q", got, jar)
+	}
+	if info.Attempts != 2 {
+		t.Errorf("got %d attempts, want 2", info.Attempts)
+	}
+	if n := atomic.LoadInt64(&tokens.calls); n != 2 {
+		t.Errorf("fetched %d tokens, want 2", n)
+	}
+}
+
+// failingRefresh sends the token "t0" and fails to refresh
+// it.
+type failingRefresh struct{}
+
+func (failingRefresh) Authenticate(req *http.Request) error {
+	req.Header.Set("Authorization", "Bearer t0")
+	return nil
+}
+
+func (failingRefresh) Refresh(ctx context.Context, rejected *http.Request) error {
+	return errors.New("token endpoint unavailable")
+}
+
+func TestFailedRefreshDoesNotLeakUpload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "job.jar")
+	if err := os.WriteFile(path, []byte("PK\x03\x04 not really a jar"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	srv := tokenServer("t1", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request sent with a token which was never refreshed")
+	})
+	defer srv.Close()
+	c, err := New(srv.URL, WithAuth(failingRefresh{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	before := runtime.NumGoroutine()
+	for i := 0; i < 20; i++ {
+		if _, err := c.UploadJar(path); err == nil {
+			t.Fatal("UploadJar succeeded, want the refresh error")
+		}
+	}
+	// Idle connections may keep a few goroutines alive, but
+	// not one multipart writer per call.
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > before+5 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if n := runtime.NumGoroutine() - before; n > 5 {
+		t.Errorf("%d goroutines left after failed refreshes", n)
+	}
+}
+
+func TestTokenSourceAuthSharesRefresh(t *testing.T) {
+	srv := tokenServer("t2", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"jobs":[]}`))
+	})
+	defer srv.Close()
+
+	tokens := &countingTokens{}
+	c, err := New(srv.URL, WithAuth(TokenSourceAuth(tokens)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := c.Jobs(); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// One fetch for the first token and one for its
+	// replacement, however many requests were rejected.
+	if n := atomic.LoadInt64(&tokens.calls); n != 2 {
+		t.Errorf("fetched %d tokens, want 2", n)
+	}
+}
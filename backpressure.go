@@ -0,0 +1,206 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const (
+	BackpressureLevelOK   = "ok"
+	BackpressureLevelLow  = "low"
+	BackpressureLevelHigh = "high"
+)
+
+// Backpressure statistics are "ok" when they are current.
+// Flink before 1.13 reports "deprecated" while it is still
+// sampling the vertex; later versions report "deprecated" for
+// vertices without backpressure metrics, e.g. a finished
+// bounded source, and never change it.
+const (
+	BackpressureStatusOK         = "ok"
+	BackpressureStatusDeprecated = "deprecated"
+)
+
+type BackpressureResp struct {
+	Status            string                `json:"status"`
+	BackpressureLevel string                `json:"backpressure-level"`
+	EndTimestamp      int64                 `json:"end-timestamp"`
+	Subtasks          []SubtaskBackpressure `json:"subtasks"`
+}
+
+type SubtaskBackpressure struct {
+	Subtask           int    `json:"subtask"`
+	AttemptNumber     int    `json:"attempt-number"`
+	BackpressureLevel string `json:"backpressure-level"`
+
+	// Ratio is the fraction of time the subtask was
+	// backpressured, IdleRatio and BusyRatio the fractions
+	// it was idle and busy.
+	Ratio     float64 `json:"ratio"`
+	IdleRatio float64 `json:"idleRatio"`
+	BusyRatio float64 `json:"busyRatio"`
+
+	OtherConcurrentAttempts []SubtaskBackpressure `json:"other-concurrent-attempts"`
+}
+
+// MaxBusyRatio returns the highest busy ratio of all
+// subtasks.
+func (r BackpressureResp) MaxBusyRatio() float64 {
+	var max float64
+	for _, s := range r.Subtasks {
+		if s.BusyRatio > max {
+			max = s.BusyRatio
+		}
+	}
+	return max
+}
+
+// Backpressure returns the backpressure, busy and idle
+// ratios of every subtask of a vertex.
+func (c *Client) Backpressure(jobID string, vertexID string) (BackpressureResp, error) {
+	return c.BackpressureCtx(context.Background(), jobID, vertexID)
+}
+
+// BackpressureCtx is like Backpressure but uses ctx to
+// cancel the request.
+func (c *Client) BackpressureCtx(ctx context.Context, jobID string, vertexID string) (BackpressureResp, error) {
+	var r BackpressureResp
+	uri := fmt.Sprintf("/jobs/%s/vertices/%s/backpressure", jobID, vertexID)
+	req, err := http.NewRequestWithContext(
+		ctx,
+		"GET",
+		c.url(uri),
+		nil,
+	)
+	if err != nil {
+		return r, err
+	}
+	b, err := c.client.Do(req)
+	if err != nil {
+		return r, err
+	}
+	err = json.Unmarshal(b, &r)
+	return r, err
+}
+
+// bottleneckBusyRatio is the busy ratio above which a vertex
+// is considered busy.
+const bottleneckBusyRatio = 0.5
+
+type Bottleneck struct {
+	Vertex       Vertice
+	Backpressure BackpressureResp
+
+	// Upstreams lists the backpressured vertices feeding
+	// into Vertex.
+	Upstreams []Vertice
+}
+
+// bottleneckSamples is how often FindBottleneck requests the
+// backpressure of the vertices until one of them is "ok", and
+// bottleneckSampleInterval the wait between two requests.
+const (
+	bottleneckSamples        = 5
+	bottleneckSampleInterval = time.Second
+)
+
+// FindBottleneck walks the vertices of a job in plan order
+// and returns the first one which is busy while one of its
+// upstream vertices is highly backpressured, but which is not
+// highly backpressured itself. It returns nil if there is no
+// such vertex.
+//
+// While no vertex reports "ok", the backpressure is requested
+// again a few times, which gives Flink before 1.13 the time
+// to sample the vertices. Once some vertex is "ok", the
+// vertices which are not are ignored, e.g. finished sources
+// on Flink 1.13 and later.
+func (c *Client) FindBottleneck(jobID string) (*Bottleneck, error) {
+	return c.FindBottleneckCtx(context.Background(), jobID)
+}
+
+// FindBottleneckCtx is like FindBottleneck but uses ctx to
+// cancel the requests.
+func (c *Client) FindBottleneckCtx(ctx context.Context, jobID string) (*Bottleneck, error) {
+	job, err := c.JobCtx(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	inputs := map[string][]string{}
+	for _, node := range job.Plan.Nodes {
+		for _, in := range node.Inputs {
+			inputs[node.ID] = append(inputs[node.ID], in.ID)
+		}
+	}
+	vertices := map[string]Vertice{}
+	for _, v := range job.Vertices {
+		vertices[v.ID] = v
+	}
+
+	// Sample every vertex with inputs and every input at
+	// once, since Flink samples the vertices in parallel.
+	var sampled []string
+	seen := map[string]bool{}
+	for _, v := range job.Vertices {
+		if len(inputs[v.ID]) == 0 {
+			continue
+		}
+		for _, id := range append([]string{v.ID}, inputs[v.ID]...) {
+			if !seen[id] {
+				seen[id] = true
+				sampled = append(sampled, id)
+			}
+		}
+	}
+	pressure := map[string]BackpressureResp{}
+	for i := 0; i < bottleneckSamples; i++ {
+		if i > 0 {
+			if err := sleep(ctx, bottleneckSampleInterval); err != nil {
+				return nil, fmt.Errorf("sample backpressure of job %s: %w", jobID, err)
+			}
+		}
+		sampledOK := false
+		for _, id := range sampled {
+			r, err := c.BackpressureCtx(ctx, jobID, id)
+			if err != nil {
+				return nil, err
+			}
+			pressure[id] = r
+			if r.Status == BackpressureStatusOK {
+				sampledOK = true
+			}
+		}
+		if sampledOK {
+			break
+		}
+	}
+
+	for _, v := range job.Vertices {
+		if len(inputs[v.ID]) == 0 {
+			continue
+		}
+		bp := pressure[v.ID]
+		if bp.Status != BackpressureStatusOK || bp.BackpressureLevel == BackpressureLevelHigh || bp.MaxBusyRatio() <= bottleneckBusyRatio {
+			continue
+		}
+		var upstreams []Vertice
+		for _, id := range inputs[v.ID] {
+			up := pressure[id]
+			if up.Status == BackpressureStatusOK && up.BackpressureLevel == BackpressureLevelHigh {
+				upstreams = append(upstreams, vertices[id])
+			}
+		}
+		if len(upstreams) > 0 {
+			return &Bottleneck{
+				Vertex:       v,
+				Backpressure: bp,
+				Upstreams:    upstreams,
+			}, nil
+		}
+	}
+	return nil, nil
+}
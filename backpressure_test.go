@@ -0,0 +1,128 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// bottleneckJob has the plan
+//
+//	src -> filter -> map <- bounded
+//
+// where bounded is a finished bounded source.
+const bottleneckJob = `{
+	"jid": "j",
+	"vertices": [
+		{"id": "src", "name": "Source"},
+		{"id": "bounded", "name": "Bounded Source"},
+		{"id": "filter", "name": "Filter"},
+		{"id": "map", "name": "Map"}
+	],
+	"plan": {"nodes": [
+		{"id": "src"},
+		{"id": "bounded"},
+		{"id": "filter", "inputs": [{"id": "src"}]},
+		{"id": "map", "inputs": [{"id": "filter"}, {"id": "bounded"}]}
+	]}
+}`
+
+// backpressureServer serves bottleneckJob. The backpressure
+// of each vertex is "deprecated" for the first sampling
+// requests, then the one given.
+type backpressureServer struct {
+	sampling     int
+	backpressure map[string]string
+
+	mu       sync.Mutex
+	requests map[string]int
+}
+
+func (s *backpressureServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/jobs/j" {
+		w.Write([]byte(bottleneckJob))
+		return
+	}
+	id := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/jobs/j/vertices/"), "/backpressure")
+	s.mu.Lock()
+	s.requests[id]++
+	n := s.requests[id]
+	s.mu.Unlock()
+	body, ok := s.backpressure[id]
+	if !ok || n <= s.sampling {
+		body = `{"status":"deprecated"}`
+	}
+	w.Write([]byte(body))
+}
+
+func TestFindBottleneck(t *testing.T) {
+	s := &backpressureServer{
+		backpressure: map[string]string{
+			"src": `{"status":"ok","backpressure-level":"high","subtasks":[{"subtask":0,"ratio":0.9,"busyRatio":0.1}]}`,
+			// filter is busy but backpressured itself, so
+			// it is not the bottleneck.
+			"filter": `{"status":"ok","backpressure-level":"high","subtasks":[{"subtask":0,"ratio":0.8,"busyRatio":0.9}]}`,
+			"map":    `{"status":"ok","backpressure-level":"ok","subtasks":[{"subtask":0,"ratio":0.0,"busyRatio":0.95},{"subtask":1,"ratio":0.0,"busyRatio":0.4}]}`,
+		},
+		requests: map[string]int{},
+	}
+	srv := httptest.NewServer(s)
+	defer srv.Close()
+	c, err := New(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+	b, err := c.FindBottleneck("j")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := time.Since(start); d > bottleneckSampleInterval/2 {
+		t.Errorf("took %s, want no wait for the finished source", d)
+	}
+	if b == nil {
+		t.Fatal("no bottleneck found")
+	}
+	if b.Vertex.ID != "map" || b.Backpressure.MaxBusyRatio() != 0.95 {
+		t.Errorf("bottleneck = %s with busy ratio %v, want map with 0.95", b.Vertex.ID, b.Backpressure.MaxBusyRatio())
+	}
+	if len(b.Upstreams) != 1 || b.Upstreams[0].ID != "filter" {
+		t.Errorf("upstreams = %+v, want filter only", b.Upstreams)
+	}
+	if n := s.requests["bounded"]; n != 1 {
+		t.Errorf("requested the finished source %d times, want 1", n)
+	}
+}
+
+func TestFindBottleneckWaitsForSampling(t *testing.T) {
+	s := &backpressureServer{
+		sampling: 1,
+		backpressure: map[string]string{
+			"src":    `{"status":"ok","backpressure-level":"high"}`,
+			"filter": `{"status":"ok","backpressure-level":"low","subtasks":[{"subtask":0,"busyRatio":0.7}]}`,
+			"map":    `{"status":"ok","backpressure-level":"ok"}`,
+		},
+		requests: map[string]int{},
+	}
+	srv := httptest.NewServer(s)
+	defer srv.Close()
+	c, err := New(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := c.FindBottleneck("j")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b == nil || b.Vertex.ID != "filter" {
+		t.Fatalf("bottleneck = %+v, want filter", b)
+	}
+	if n := s.requests["filter"]; n != 2 {
+		t.Errorf("requested filter %d times, want 2", n)
+	}
+}
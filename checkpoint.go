@@ -0,0 +1,413 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+type CheckpointsResp struct {
+	Counts  Counts                 `json:"counts"`
+	Summary Summary                `json:"summary"`
+	Latest  Latest                 `json:"latest"`
+	History []CheckpointStatistics `json:"history"`
+}
+
+type Counts struct {
+	Restored   int `json:"restored"`
+	Total      int `json:"total"`
+	InProgress int `json:"in_progress"`
+	Completed  int `json:"completed"`
+	Failed     int `json:"failed"`
+}
+
+// Summary summarizes the completed checkpoints of a job.
+type Summary struct {
+	CheckpointedSize  MinMaxAvg `json:"checkpointed_size"`
+	StateSize         MinMaxAvg `json:"state_size"`
+	End2EndDuration   MinMaxAvg `json:"end_to_end_duration"`
+	AlignmentBuffered MinMaxAvg `json:"alignment_buffered"`
+	ProcessedData     MinMaxAvg `json:"processed_data"`
+	PersistedData     MinMaxAvg `json:"persisted_data"`
+}
+
+// Latest holds the latest checkpoint of each kind. A field
+// is zero, as reported by IsZero, if the job has no such
+// checkpoint.
+type Latest struct {
+	Completed CheckpointStatistics         `json:"completed"`
+	Savepoint CheckpointStatistics         `json:"savepoint"`
+	Failed    CheckpointStatistics         `json:"failed"`
+	Restored  RestoredCheckpointStatistics `json:"restored"`
+}
+
+type RestoredCheckpointStatistics struct {
+	ID               int64  `json:"id"`
+	RestoreTimestamp int64  `json:"restore_timestamp"`
+	IsSavepoint      bool   `json:"is_savepoint"`
+	ExternalPath     string `json:"external_path"`
+}
+
+// IsZero reports whether r describes no checkpoint, e.g.
+// because the job was never restored.
+func (r RestoredCheckpointStatistics) IsZero() bool {
+	return r.ID == 0
+}
+
+// RestoreTime returns the time the job was restored from
+// the checkpoint.
+func (r RestoredCheckpointStatistics) RestoreTime() time.Time {
+	return time.UnixMilli(r.RestoreTimestamp)
+}
+
+// Checkpoints returns checkpointing statistics for a job.
+func (c *Client) Checkpoints(jobID string) (CheckpointsResp, error) {
+	return c.CheckpointsCtx(context.Background(), jobID)
+}
+
+// CheckpointsCtx is like Checkpoints but uses ctx to cancel
+// the request.
+func (c *Client) CheckpointsCtx(ctx context.Context, jobID string) (CheckpointsResp, error) {
+	var r CheckpointsResp
+	uri := fmt.Sprintf("/jobs/%s/checkpoints", jobID)
+	req, err := http.NewRequestWithContext(
+		ctx,
+		"GET",
+		c.url(uri),
+		nil,
+	)
+	if err != nil {
+		return r, err
+	}
+	b, err := c.client.Do(req)
+	if err != nil {
+		return r, err
+	}
+	err = json.Unmarshal(b, &r)
+	return r, err
+}
+
+// MinMaxAvg summarizes a statistic over several
+// checkpoints or subtasks. Percentiles are only reported by
+// recent Flink versions and are NaN otherwise.
+type MinMaxAvg struct {
+	Min  int64   `json:"min"`
+	Max  int64   `json:"max"`
+	Avg  int64   `json:"avg"`
+	P50  float64 `json:"p50"`
+	P90  float64 `json:"p90"`
+	P95  float64 `json:"p95"`
+	P99  float64 `json:"p99"`
+	P999 float64 `json:"p999"`
+}
+
+func (s *MinMaxAvg) UnmarshalJSON(b []byte) error {
+	var aux struct {
+		Min  int64       `json:"min"`
+		Max  int64       `json:"max"`
+		Avg  int64       `json:"avg"`
+		P50  interface{} `json:"p50"`
+		P90  interface{} `json:"p90"`
+		P95  interface{} `json:"p95"`
+		P99  interface{} `json:"p99"`
+		P999 interface{} `json:"p999"`
+	}
+	if err := json.Unmarshal(b, &aux); err != nil {
+		return err
+	}
+	s.Min, s.Max, s.Avg = aux.Min, aux.Max, aux.Avg
+	var err error
+	for _, p := range []struct {
+		dst *float64
+		v   interface{}
+	}{
+		{&s.P50, aux.P50},
+		{&s.P90, aux.P90},
+		{&s.P95, aux.P95},
+		{&s.P99, aux.P99},
+		{&s.P999, aux.P999},
+	} {
+		if *p.dst, err = jsonFloat(p.v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+const (
+	CheckpointInProgress = "in_progress"
+	CheckpointCompleted  = "completed"
+	CheckpointFailed     = "failed"
+)
+
+// CheckpointStatistics describes a single checkpoint, which
+// may be in progress, completed or failed.
+type CheckpointStatistics struct {
+	// ClassName is CheckpointInProgress, CheckpointCompleted
+	// or CheckpointFailed.
+	ClassName string `json:"className"`
+
+	ID              int64  `json:"id"`
+	Status          string `json:"status"`
+	IsSavepoint     bool   `json:"is_savepoint"`
+	SavepointFormat string `json:"savepointFormat"`
+	CheckpointType  string `json:"checkpoint_type"`
+
+	TriggerTimestamp   int64 `json:"trigger_timestamp"`
+	LatestAckTimestamp int64 `json:"latest_ack_timestamp"`
+
+	// Sizes are in bytes, durations in milliseconds.
+	CheckpointedSize  int64 `json:"checkpointed_size"`
+	StateSize         int64 `json:"state_size"`
+	End2EndDuration   int64 `json:"end_to_end_duration"`
+	AlignmentBuffered int64 `json:"alignment_buffered"`
+	ProcessedData     int64 `json:"processed_data"`
+	PersistedData     int64 `json:"persisted_data"`
+
+	NumSubtasks             int `json:"num_subtasks"`
+	NumAcknowledgedSubtasks int `json:"num_acknowledged_subtasks"`
+
+	// Tasks maps vertex IDs to the statistics of the
+	// vertex. It is only set by CheckpointDetail.
+	Tasks map[string]TaskCheckpointStatistics `json:"tasks"`
+
+	// ExternalPath and Discarded are set for completed
+	// checkpoints.
+	ExternalPath string `json:"external_path"`
+	Discarded    bool   `json:"discarded"`
+
+	// FailureTimestamp and FailureMessage are set for
+	// failed checkpoints.
+	FailureTimestamp int64  `json:"failure_timestamp"`
+	FailureMessage   string `json:"failure_message"`
+}
+
+// CompletedCheckpointsStatics is the former name of
+// CheckpointStatistics.
+//
+// Deprecated: use CheckpointStatistics.
+type CompletedCheckpointsStatics = CheckpointStatistics
+
+// IsZero reports whether s describes no checkpoint, e.g.
+// Latest.Failed of a job without failed checkpoints.
+// Checkpoint IDs start at 1.
+func (s CheckpointStatistics) IsZero() bool {
+	return s.ID == 0
+}
+
+// TriggerTime returns the time the checkpoint was
+// triggered.
+func (s CheckpointStatistics) TriggerTime() time.Time {
+	return time.UnixMilli(s.TriggerTimestamp)
+}
+
+// LatestAckTime returns the time of the latest
+// acknowledgement by a subtask, or the zero time if no
+// subtask acknowledged the checkpoint yet.
+func (s CheckpointStatistics) LatestAckTime() time.Time {
+	return msTime(s.LatestAckTimestamp)
+}
+
+// FailureTime returns the time the checkpoint failed, or the
+// zero time if it did not fail.
+func (s CheckpointStatistics) FailureTime() time.Time {
+	return msTime(s.FailureTimestamp)
+}
+
+// Duration returns the end to end duration of the
+// checkpoint.
+func (s CheckpointStatistics) Duration() time.Duration {
+	return time.Duration(s.End2EndDuration) * time.Millisecond
+}
+
+// TaskCheckpointStatistics describes the part of a
+// checkpoint taken by the subtasks of one vertex.
+type TaskCheckpointStatistics struct {
+	ID     int64  `json:"id"`
+	Status string `json:"status"`
+
+	LatestAckTimestamp int64 `json:"latest_ack_timestamp"`
+
+	CheckpointedSize  int64 `json:"checkpointed_size"`
+	StateSize         int64 `json:"state_size"`
+	End2EndDuration   int64 `json:"end_to_end_duration"`
+	AlignmentBuffered int64 `json:"alignment_buffered"`
+	ProcessedData     int64 `json:"processed_data"`
+	PersistedData     int64 `json:"persisted_data"`
+
+	NumSubtasks             int `json:"num_subtasks"`
+	NumAcknowledgedSubtasks int `json:"num_acknowledged_subtasks"`
+}
+
+// TaskCheckpointsStatics is the former name of
+// TaskCheckpointStatistics.
+//
+// Deprecated: use TaskCheckpointStatistics.
+type TaskCheckpointsStatics = TaskCheckpointStatistics
+
+// LatestAckTime returns the time of the latest
+// acknowledgement by a subtask of the vertex, or the zero
+// time if there is none.
+func (s TaskCheckpointStatistics) LatestAckTime() time.Time {
+	return msTime(s.LatestAckTimestamp)
+}
+
+// Duration returns the end to end duration of the
+// checkpoint for the vertex.
+func (s TaskCheckpointStatistics) Duration() time.Duration {
+	return time.Duration(s.End2EndDuration) * time.Millisecond
+}
+
+// CheckpointDetail returns the statistics of a checkpoint,
+// including the statistics of every vertex.
+func (c *Client) CheckpointDetail(jobID string, checkpointID int64) (CheckpointStatistics, error) {
+	return c.CheckpointDetailCtx(context.Background(), jobID, checkpointID)
+}
+
+// CheckpointDetailCtx is like CheckpointDetail but uses ctx
+// to cancel the request.
+func (c *Client) CheckpointDetailCtx(ctx context.Context, jobID string, checkpointID int64) (CheckpointStatistics, error) {
+	var r CheckpointStatistics
+	uri := fmt.Sprintf("/jobs/%s/checkpoints/details/%d", jobID, checkpointID)
+	req, err := http.NewRequestWithContext(
+		ctx,
+		"GET",
+		c.url(uri),
+		nil,
+	)
+	if err != nil {
+		return r, err
+	}
+	b, err := c.client.Do(req)
+	if err != nil {
+		return r, err
+	}
+	err = json.Unmarshal(b, &r)
+	return r, err
+}
+
+type TaskCheckpointDetailResp struct {
+	TaskCheckpointStatistics
+
+	Summary  SubtaskCheckpointSummary      `json:"summary"`
+	Subtasks []SubtaskCheckpointStatistics `json:"subtasks"`
+}
+
+type SubtaskCheckpointSummary struct {
+	CheckpointedSize   MinMaxAvg                 `json:"checkpointed_size"`
+	StateSize          MinMaxAvg                 `json:"state_size"`
+	End2EndDuration    MinMaxAvg                 `json:"end_to_end_duration"`
+	CheckpointDuration CheckpointDurationSummary `json:"checkpoint_duration"`
+	Alignment          AlignmentSummary          `json:"alignment"`
+	StartDelay         MinMaxAvg                 `json:"start_delay"`
+}
+
+type CheckpointDurationSummary struct {
+	Sync  MinMaxAvg `json:"sync"`
+	Async MinMaxAvg `json:"async"`
+}
+
+type AlignmentSummary struct {
+	Buffered  MinMaxAvg `json:"buffered"`
+	Processed MinMaxAvg `json:"processed"`
+	Persisted MinMaxAvg `json:"persisted"`
+	Duration  MinMaxAvg `json:"duration"`
+}
+
+const (
+	SubtaskCheckpointCompleted       = "completed"
+	SubtaskCheckpointPendingOrFailed = "pending_or_failed"
+)
+
+// SubtaskCheckpointStatistics describes the part of a
+// checkpoint taken by one subtask. Only Index and Status
+// are set unless the subtask completed its checkpoint.
+type SubtaskCheckpointStatistics struct {
+	Index  int    `json:"index"`
+	Status string `json:"status"`
+
+	AckTimestamp     int64 `json:"ack_timestamp"`
+	End2EndDuration  int64 `json:"end_to_end_duration"`
+	StateSize        int64 `json:"state_size"`
+	CheckpointedSize int64 `json:"checkpointed_size"`
+
+	Checkpoint CheckpointDuration `json:"checkpoint"`
+	Alignment  Alignment          `json:"alignment"`
+
+	// StartDelay is the time in milliseconds between the
+	// trigger of the checkpoint and the moment the subtask
+	// started it.
+	StartDelay int64 `json:"start_delay"`
+
+	UnalignedCheckpoint bool `json:"unaligned_checkpoint"`
+	Aborted             bool `json:"aborted"`
+}
+
+// AckTime returns the time the subtask acknowledged the
+// checkpoint, or the zero time if it did not.
+func (s SubtaskCheckpointStatistics) AckTime() time.Time {
+	return msTime(s.AckTimestamp)
+}
+
+// Duration returns the end to end duration of the
+// checkpoint for the subtask.
+func (s SubtaskCheckpointStatistics) Duration() time.Duration {
+	return time.Duration(s.End2EndDuration) * time.Millisecond
+}
+
+// CheckpointDuration holds the durations in milliseconds of
+// the synchronous and asynchronous parts of a checkpoint.
+type CheckpointDuration struct {
+	Sync  int64 `json:"sync"`
+	Async int64 `json:"async"`
+}
+
+// Alignment describes the barrier alignment of a subtask:
+// the bytes buffered, processed and persisted while
+// aligning, and the duration in milliseconds.
+type Alignment struct {
+	Buffered  int64 `json:"buffered"`
+	Processed int64 `json:"processed"`
+	Persisted int64 `json:"persisted"`
+	Duration  int64 `json:"duration"`
+}
+
+// CheckpointSubtasks returns the statistics of every
+// subtask of a vertex for a checkpoint.
+func (c *Client) CheckpointSubtasks(jobID string, checkpointID int64, vertexID string) (TaskCheckpointDetailResp, error) {
+	return c.CheckpointSubtasksCtx(context.Background(), jobID, checkpointID, vertexID)
+}
+
+// CheckpointSubtasksCtx is like CheckpointSubtasks but uses
+// ctx to cancel the request.
+func (c *Client) CheckpointSubtasksCtx(ctx context.Context, jobID string, checkpointID int64, vertexID string) (TaskCheckpointDetailResp, error) {
+	var r TaskCheckpointDetailResp
+	uri := fmt.Sprintf("/jobs/%s/checkpoints/details/%d/subtasks/%s", jobID, checkpointID, vertexID)
+	req, err := http.NewRequestWithContext(
+		ctx,
+		"GET",
+		c.url(uri),
+		nil,
+	)
+	if err != nil {
+		return r, err
+	}
+	b, err := c.client.Do(req)
+	if err != nil {
+		return r, err
+	}
+	err = json.Unmarshal(b, &r)
+	return r, err
+}
+
+// msTime converts a timestamp in milliseconds to a
+// time.Time. Flink reports unset timestamps as 0 or -1,
+// which become the zero time.
+func msTime(ms int64) time.Time {
+	if ms <= 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(ms)
+}
@@ -0,0 +1,67 @@
+package api
+
+import (
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestCheckpointsDecoding(t *testing.T) {
+	body, err := os.ReadFile("testdata/checkpoints.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(body)
+	}))
+	defer srv.Close()
+	c, err := New(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := c.Checkpoints("j")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if r.Counts != (Counts{Total: 3, InProgress: 1, Completed: 1, Failed: 1}) {
+		t.Errorf("Counts = %+v", r.Counts)
+	}
+
+	// Latest checkpoints which don't exist are null.
+	if r.Latest.Completed.IsZero() || r.Latest.Completed.ID != 2 {
+		t.Errorf("Latest.Completed = %+v, want checkpoint 2", r.Latest.Completed)
+	}
+	if !r.Latest.Failed.IsZero() || !r.Latest.Savepoint.IsZero() || !r.Latest.Restored.IsZero() {
+		t.Errorf("Latest = %+v, want no failed, savepoint or restored checkpoint", r.Latest)
+	}
+
+	// Percentiles may be numbers, "NaN" or missing.
+	if s := r.Summary.CheckpointedSize; s.Avg != 1024 || s.P99 != 1024 {
+		t.Errorf("Summary.CheckpointedSize = %+v, want avg and p99 1024", s)
+	}
+	if s := r.Summary.StateSize; s.Max != 2048 || !math.IsNaN(s.P50) || !math.IsNaN(s.P999) {
+		t.Errorf("Summary.StateSize = %+v, want max 2048 and NaN percentiles", s)
+	}
+	if s := r.Summary.End2EndDuration; s.Min != 35 || !math.IsNaN(s.P90) {
+		t.Errorf("Summary.End2EndDuration = %+v, want min 35 and NaN percentiles", s)
+	}
+
+	if len(r.History) != 3 {
+		t.Fatalf("got %d history entries, want 3", len(r.History))
+	}
+	inProgress, completed, failed := r.History[0], r.History[1], r.History[2]
+	if inProgress.ClassName != CheckpointInProgress || inProgress.ID != 3 || !inProgress.LatestAckTime().IsZero() {
+		t.Errorf("History[0] = %+v, want checkpoint 3 in progress without acks", inProgress)
+	}
+	if completed.ClassName != CheckpointCompleted || completed.ExternalPath != "file:/tmp/checkpoints/chk-2" || completed.Duration() != 35*time.Millisecond {
+		t.Errorf("History[1] = %+v, want completed checkpoint 2", completed)
+	}
+	if failed.ClassName != CheckpointFailed || failed.FailureMessage != "Checkpoint expired before completing." || !failed.FailureTime().Equal(time.UnixMilli(1700000600000)) {
+		t.Errorf("History[2] = %+v, want failed checkpoint 1", failed)
+	}
+}
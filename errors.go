@@ -0,0 +1,92 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// APIError is returned when the job manager answers with a
+// status other than 2xx.
+type APIError struct {
+	StatusCode int
+	Method     string
+	URL        string
+
+	// Errors holds the messages of the {"errors": [...]}
+	// payload Flink sends with a failed request.
+	Errors []string
+
+	// StackTrace holds the root cause stack trace reported by
+	// the server, if any.
+	StackTrace string
+
+	// Body is the raw response body.
+	Body []byte
+}
+
+func (e *APIError) Error() string {
+	msg := strings.Join(e.Errors, "; ")
+	if len(e.Errors) == 0 {
+		msg = strings.TrimSpace(string(e.Body))
+	}
+	s := fmt.Sprintf("flink: %s %s: %d %s", e.Method, e.URL, e.StatusCode, http.StatusText(e.StatusCode))
+	if msg != "" {
+		s += ": " + msg
+	}
+	return s
+}
+
+const (
+	serverExceptionPrefix = "<Exception on server side:"
+	serverExceptionSuffix = "End of exception on server side>"
+)
+
+func newAPIError(req *http.Request, resp *http.Response, body []byte) *APIError {
+	if resp.Request != nil {
+		req = resp.Request
+	}
+	e := &APIError{
+		StatusCode: resp.StatusCode,
+		Method:     req.Method,
+		URL:        req.URL.String(),
+		Body:       body,
+	}
+	var payload struct {
+		Errors []string `json:"errors"`
+	}
+	if json.Unmarshal(body, &payload) != nil {
+		return e
+	}
+	for _, msg := range payload.Errors {
+		if strings.HasPrefix(msg, serverExceptionPrefix) {
+			msg = strings.TrimPrefix(msg, serverExceptionPrefix)
+			msg = strings.TrimSuffix(msg, serverExceptionSuffix)
+			e.StackTrace = strings.TrimSpace(msg)
+			continue
+		}
+		e.Errors = append(e.Errors, msg)
+	}
+	return e
+}
+
+// IsStatus reports whether err is an *APIError with the
+// given HTTP status code.
+func IsStatus(err error, code int) bool {
+	var e *APIError
+	return errors.As(err, &e) && e.StatusCode == code
+}
+
+// IsNotFound reports whether err is a 404 answer, e.g. for a
+// job or jar which does not exist.
+func IsNotFound(err error) bool {
+	return IsStatus(err, http.StatusNotFound)
+}
+
+// IsConflict reports whether err is a 409 answer, e.g. for
+// an operation which conflicts with the job state.
+func IsConflict(err error) bool {
+	return IsStatus(err, http.StatusConflict)
+}
@@ -0,0 +1,91 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// notFoundBody is what Flink answers for a job which does not
+// exist.
+const notFoundBody = `{"errors":["Job 8ea123d2bdc3064f36b92889e43803ee not found","<Exception on server side:\norg.apache.flink.runtime.rest.NotFoundException: Job 8ea123d2bdc3064f36b92889e43803ee not found\n\tat org.apache.flink.runtime.rest.handler.job.AbstractExecutionGraphHandler.lambda$handleRequest$1(AbstractExecutionGraphHandler.java:99)\n\tat java.base/java.util.concurrent.CompletableFuture.uniExceptionally(CompletableFuture.java:986)\nCaused by: org.apache.flink.runtime.messages.FlinkJobNotFoundException: Could not find Flink job (8ea123d2bdc3064f36b92889e43803ee)\n\t... 3 more\n\nEnd of exception on server side>"]}`
+
+func TestAPIError(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     int
+		body       string
+		errors     []string
+		stackTrace string
+		notFound   bool
+		conflict   bool
+	}{
+		{
+			name:   "job not found",
+			status: http.StatusNotFound,
+			body:   notFoundBody,
+			errors: []string{"Job 8ea123d2bdc3064f36b92889e43803ee not found"},
+			stackTrace: "org.apache.flink.runtime.rest.NotFoundException: Job 8ea123d2bdc3064f36b92889e43803ee not found\n" +
+				"\tat org.apache.flink.runtime.rest.handler.job.AbstractExecutionGraphHandler.lambda$handleRequest$1(AbstractExecutionGraphHandler.java:99)\n" +
+				"\tat java.base/java.util.concurrent.CompletableFuture.uniExceptionally(CompletableFuture.java:986)\n" +
+				"Caused by: org.apache.flink.runtime.messages.FlinkJobNotFoundException: Could not find Flink job (8ea123d2bdc3064f36b92889e43803ee)\n" +
+				"\t... 3 more",
+			notFound: true,
+		},
+		{
+			name:     "conflict without stack trace",
+			status:   http.StatusConflict,
+			body:     `{"errors":["Operation not found under key: 8ea123d2bdc3064f36b92889e43803ee"]}`,
+			errors:   []string{"Operation not found under key: 8ea123d2bdc3064f36b92889e43803ee"},
+			conflict: true,
+		},
+		{
+			name:   "body which is not JSON",
+			status: http.StatusInternalServerError,
+			body:   "Internal Server Error",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+			c, err := New(srv.URL, WithRetryPolicy(RetryPolicy{}))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = c.Job("8ea123d2bdc3064f36b92889e43803ee")
+			e, ok := err.(*APIError)
+			if !ok {
+				t.Fatalf("err = %#v, want an *APIError", err)
+			}
+			if e.StatusCode != tt.status || e.Method != "GET" {
+				t.Errorf("got %s %d, want GET %d", e.Method, e.StatusCode, tt.status)
+			}
+			if fmt.Sprint(e.Errors) != fmt.Sprint(tt.errors) {
+				t.Errorf("Errors = %q, want %q", e.Errors, tt.errors)
+			}
+			if e.StackTrace != tt.stackTrace {
+				t.Errorf("StackTrace = %q, want %q", e.StackTrace, tt.stackTrace)
+			}
+			if string(e.Body) != tt.body {
+				t.Errorf("Body = %q, want %q", e.Body, tt.body)
+			}
+
+			wrapped := fmt.Errorf("load job: %w", err)
+			if got := IsNotFound(wrapped); got != tt.notFound {
+				t.Errorf("IsNotFound = %v, want %v", got, tt.notFound)
+			}
+			if got := IsConflict(wrapped); got != tt.conflict {
+				t.Errorf("IsConflict = %v, want %v", got, tt.conflict)
+			}
+			if !IsStatus(wrapped, tt.status) {
+				t.Errorf("IsStatus(%d) = false", tt.status)
+			}
+		})
+	}
+}
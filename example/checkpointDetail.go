@@ -0,0 +1,35 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/logi-camp/go-flink-client"
+)
+
+func main() {
+	c, err := api.New(os.Getenv("FLINK_API"))
+	if err != nil {
+		panic(err)
+	}
+
+	jobID := "8ea123d2bdc3064f36b92889e43803ee"
+	var checkpointID int64 = 42
+	// checkpoint detail test
+	detail, err := c.CheckpointDetail(jobID, checkpointID)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(detail)
+
+	// checkpoint subtasks test
+	for vertexID := range detail.Tasks {
+		subtasks, err := c.CheckpointSubtasks(jobID, checkpointID, vertexID)
+		if err != nil {
+			panic(err)
+		}
+		for _, s := range subtasks.Subtasks {
+			fmt.Println(vertexID, s.Index, s.Status, s.End2EndDuration)
+		}
+	}
+}
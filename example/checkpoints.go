@@ -18,4 +18,10 @@ func main() {
 		panic(err)
 	}
 	fmt.Println(v)
+	if !v.Latest.Completed.IsZero() {
+		fmt.Println(v.Latest.Completed.ID, v.Latest.Completed.Duration())
+	}
+	for _, h := range v.History {
+		fmt.Println(h.ID, h.ClassName, h.TriggerTime())
+	}
 }
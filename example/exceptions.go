@@ -0,0 +1,24 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/logi-camp/go-flink-client"
+)
+
+func main() {
+	c, err := api.New(os.Getenv("FLINK_API"))
+	if err != nil {
+		panic(err)
+	}
+
+	// exceptions test
+	r, err := c.Exceptions("8ea123d2bdc3064f36b92889e43803ee", 100)
+	if err != nil {
+		panic(err)
+	}
+	for _, g := range api.GroupExceptions(r.ExceptionHistory.Entries) {
+		fmt.Println(g.Count, g.Class, g.TopFrame)
+	}
+}
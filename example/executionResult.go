@@ -0,0 +1,30 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/logi-camp/go-flink-client"
+)
+
+func main() {
+	c, err := api.New(os.Getenv("FLINK_API"))
+	if err != nil {
+		panic(err)
+	}
+
+	jobID := "8ea123d2bdc3064f36b92889e43803ee"
+	// execution result test
+	r, err := c.ExecutionResult(jobID)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(r.Status.Id, r.JobExecutionResult)
+
+	// accumulators test
+	acc, err := c.Accumulators(jobID, false)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(acc.UserTaskAccumulators)
+}
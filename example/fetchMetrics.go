@@ -0,0 +1,30 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/logi-camp/go-flink-client"
+)
+
+func main() {
+	c, err := api.New(os.Getenv("FLINK_API"))
+	if err != nil {
+		panic(err)
+	}
+
+	jobID := "8ea123d2bdc3064f36b92889e43803ee"
+	vertexID := "cbc357ccb763df2852fee8c4fc7d55f2"
+
+	// fetch metrics test
+	metrics, err := c.FetchMetrics(context.Background(), api.VertexScope(jobID, vertexID), api.FetchMetricsOpts{
+		Match: []string{"*.numRecordsInPerSecond", "*.numRecordsOutPerSecond"},
+	})
+	if err != nil {
+		panic(err)
+	}
+	for id, m := range metrics {
+		fmt.Println(id, m.Value)
+	}
+}
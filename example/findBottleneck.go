@@ -0,0 +1,26 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/logi-camp/go-flink-client"
+)
+
+func main() {
+	c, err := api.New(os.Getenv("FLINK_API"))
+	if err != nil {
+		panic(err)
+	}
+
+	// bottleneck test
+	b, err := c.FindBottleneck("8ea123d2bdc3064f36b92889e43803ee")
+	if err != nil {
+		panic(err)
+	}
+	if b == nil {
+		fmt.Println("no bottleneck")
+		return
+	}
+	fmt.Println(b.Vertex.Name, b.Backpressure.MaxBusyRatio())
+}
@@ -0,0 +1,23 @@
+package main
+
+import (
+	"os"
+
+	"github.com/logi-camp/go-flink-client"
+)
+
+func main() {
+	c, err := api.New(os.Getenv("FLINK_API"))
+	if err != nil {
+		panic(err)
+	}
+
+	// flame graph test
+	fg, err := c.FlameGraph("8ea123d2bdc3064f36b92889e43803ee", "cbc357ccb763df2852fee8c4fc7d55f2", api.FlameGraphOnCPU)
+	if err != nil {
+		panic(err)
+	}
+	if err := fg.WriteFolded(os.Stdout); err != nil {
+		panic(err)
+	}
+}
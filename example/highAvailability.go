@@ -0,0 +1,31 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/logi-camp/go-flink-client"
+)
+
+func main() {
+	c, err := api.New(
+		os.Getenv("FLINK_API"),
+		api.WithEndpoints(os.Getenv("FLINK_API_STANDBY")),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	for _, s := range c.CheckEndpoints(context.Background()) {
+		fmt.Println(s.Addr, s.Healthy, s.Err)
+	}
+
+	// high availability test
+	var info api.CallInfo
+	jobs, err := c.JobsCtx(api.WithCallInfo(context.Background(), &info))
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(jobs, info.Endpoint)
+}
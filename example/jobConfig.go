@@ -0,0 +1,30 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/logi-camp/go-flink-client"
+)
+
+func main() {
+	c, err := api.New(os.Getenv("FLINK_API"))
+	if err != nil {
+		panic(err)
+	}
+
+	jobID := "8ea123d2bdc3064f36b92889e43803ee"
+	// job config test
+	config, err := c.JobConfig(jobID)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(config)
+
+	// checkpoint config test
+	checkpointConfig, err := c.CheckpointConfig(jobID)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(checkpointConfig)
+}
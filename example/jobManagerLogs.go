@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/logi-camp/go-flink-client"
+)
+
+func main() {
+	c, err := api.New(os.Getenv("FLINK_API"))
+	if err != nil {
+		panic(err)
+	}
+
+	// job manager logs test
+	logs, err := c.JobManagerLogs()
+	if err != nil {
+		panic(err)
+	}
+	for _, l := range logs.Logs {
+		fmt.Println(l.Name, l.Size, l.ModTime())
+	}
+
+	log, err := c.JobManagerLog()
+	if err != nil {
+		panic(err)
+	}
+	defer log.Close()
+	io.Copy(os.Stdout, log)
+
+	// job manager environment test
+	env, err := c.JobManagerEnvironment()
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(env.JVM.Version, env.JVM.Options)
+
+	// job manager thread dump test
+	dump, err := c.JobManagerThreadDump()
+	if err != nil {
+		panic(err)
+	}
+	for _, t := range dump.ThreadInfos {
+		fmt.Println(t.ThreadName)
+	}
+}
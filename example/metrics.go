@@ -0,0 +1,41 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/logi-camp/go-flink-client"
+)
+
+func main() {
+	c, err := api.New(os.Getenv("FLINK_API"))
+	if err != nil {
+		panic(err)
+	}
+
+	jobID := "8ea123d2bdc3064f36b92889e43803ee"
+	vertexID := "cbc357ccb763df2852fee8c4fc7d55f2"
+
+	// job metrics test
+	job, err := c.Metrics(api.JobScope(jobID), api.MetricsOpts{
+		Metrics: []string{"uptime", "numRestarts"},
+	})
+	if err != nil {
+		panic(err)
+	}
+	for _, m := range job {
+		fmt.Println(m.ID, m.Value)
+	}
+
+	// aggregated subtask metrics test
+	subtasks, err := c.Metrics(api.SubtasksScope(jobID, vertexID), api.MetricsOpts{
+		Metrics: []string{"numRecordsInPerSecond"},
+		Agg:     []string{api.AggMin, api.AggMax, api.AggSkew},
+	})
+	if err != nil {
+		panic(err)
+	}
+	for _, m := range subtasks {
+		fmt.Println(m.ID, m.Min, m.Max, m.Skew)
+	}
+}
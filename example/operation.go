@@ -0,0 +1,27 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/logi-camp/go-flink-client"
+)
+
+func main() {
+	c, err := api.New(os.Getenv("FLINK_API"))
+	if err != nil {
+		panic(err)
+	}
+
+	// checkpoint operation test
+	op, err := c.TriggerCheckpoint("2bd452ba193d1575a4acc9ed09f896ea", api.CheckpointTypeFull)
+	if err != nil {
+		panic(err)
+	}
+	result, err := op.Wait(context.Background())
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(result.CheckpointID)
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	api "github.com/logi-camp/go-flink-client"
+)
+
+func main() {
+	c, err := api.New(os.Getenv("FLINK_API"))
+	if err != nil {
+		panic(err)
+	}
+
+	opts := api.RunOpts{
+		JarID: "8c0c2226-b532-4d9b-b698-8aa649694bb9_test.jar",
+	}
+	// run and wait test
+	r, err := c.RunJarAndWait(context.Background(), opts, api.WaitOpts{Timeout: time.Hour})
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(r.Job.State, r.Accumulators.UserTaskAccumulators)
+	if r.RootException != nil {
+		fmt.Println(r.RootException.StackTrace)
+	}
+}
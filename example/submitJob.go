@@ -0,0 +1,26 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/logi-camp/go-flink-client"
+)
+
+func main() {
+	c, err := api.New(os.Getenv("FLINK_API"))
+	if err != nil {
+		panic(err)
+	}
+
+	opts := api.SubmitJobOpts{
+		JobGraphFile: "./testdata/jobGraph.bin",
+		JarFiles:     []string{"./testdata/test.jar"},
+	}
+	// submit job test
+	resp, err := c.SubmitJob(opts)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(resp.JobID())
+}
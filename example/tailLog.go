@@ -0,0 +1,32 @@
+package main
+
+import (
+	"context"
+	"io"
+	"os"
+	"os/signal"
+	"regexp"
+
+	"github.com/logi-camp/go-flink-client"
+)
+
+func main() {
+	c, err := api.New(os.Getenv("FLINK_API"))
+	if err != nil {
+		panic(err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	// tail log test
+	log, err := c.TailLog(ctx, api.JobManagerLogSource(""), api.TailOpts{
+		Lines:  20,
+		Filter: regexp.MustCompile(`WARN|ERROR`),
+	})
+	if err != nil {
+		panic(err)
+	}
+	defer log.Close()
+	io.Copy(os.Stdout, log)
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/logi-camp/go-flink-client"
+)
+
+func main() {
+	c, err := api.New(os.Getenv("FLINK_API"))
+	if err != nil {
+		panic(err)
+	}
+
+	// task managers test
+	tms, err := c.TaskManagers()
+	if err != nil {
+		panic(err)
+	}
+	for _, tm := range tms.TaskManagers {
+		fmt.Println(tm.ID, tm.FreeSlots, tm.SlotsNumber)
+	}
+	if len(tms.TaskManagers) == 0 {
+		return
+	}
+	id := tms.TaskManagers[0].ID
+
+	// task manager detail test
+	tm, err := c.TaskManager(id)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(tm.Metrics.HeapUsed, tm.Metrics.HeapMax)
+
+	// task manager log test
+	logs, err := c.TaskManagerLogs(id)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(logs)
+
+	log, err := c.TaskManagerLog(id)
+	if err != nil {
+		panic(err)
+	}
+	defer log.Close()
+	io.Copy(os.Stdout, log)
+
+	// thread dump test
+	dump, err := c.TaskManagerThreadDump(id)
+	if err != nil {
+		panic(err)
+	}
+	for _, t := range dump.ThreadInfos {
+		fmt.Println(t.StringifiedThreadInfo)
+	}
+}
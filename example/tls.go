@@ -0,0 +1,31 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/logi-camp/go-flink-client"
+)
+
+func main() {
+	opts := []api.Option{
+		api.WithTimeout(10 * time.Second),
+		api.WithCAFile(os.Getenv("FLINK_CA_FILE")),
+	}
+	// mutual TLS is optional
+	if cert := os.Getenv("FLINK_CLIENT_CERT"); cert != "" {
+		opts = append(opts, api.WithClientCertFile(cert, os.Getenv("FLINK_CLIENT_KEY")))
+	}
+	c, err := api.New(os.Getenv("FLINK_API"), opts...)
+	if err != nil {
+		panic(err)
+	}
+
+	// tls test
+	config, err := c.Config()
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(config)
+}
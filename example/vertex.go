@@ -0,0 +1,24 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/logi-camp/go-flink-client"
+)
+
+func main() {
+	c, err := api.New(os.Getenv("FLINK_API"))
+	if err != nil {
+		panic(err)
+	}
+
+	// vertex test
+	v, err := c.Vertex("8ea123d2bdc3064f36b92889e43803ee", "cbc357ccb763df2852fee8c4fc7d55f2")
+	if err != nil {
+		panic(err)
+	}
+	for _, s := range v.Subtasks {
+		fmt.Println(s.Subtask, s.Status, s.Endpoint, s.Attempt)
+	}
+}
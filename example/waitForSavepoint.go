@@ -0,0 +1,32 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/logi-camp/go-flink-client"
+)
+
+func main() {
+	c, err := api.New(os.Getenv("FLINK_API"))
+	if err != nil {
+		panic(err)
+	}
+
+	jobID := "2bd452ba193d1575a4acc9ed09f896ea"
+	v, err := c.SavePoints(jobID, "test", false)
+	if err != nil {
+		panic(err)
+	}
+
+	// wait for savepoint test
+	location, err := c.WaitForSavepoint(context.Background(), jobID, v.RequestID, api.WaitOpts{
+		Timeout: 10 * time.Minute,
+	})
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(location)
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/logi-camp/go-flink-client"
+)
+
+func main() {
+	c, err := api.New(os.Getenv("FLINK_API"))
+	if err != nil {
+		panic(err)
+	}
+
+	// watermark lag test
+	lags, err := c.WatermarkLags(context.Background(), "8ea123d2bdc3064f36b92889e43803ee", api.WatermarkLagOpts{})
+	if err != nil {
+		panic(err)
+	}
+	for _, l := range lags {
+		if l.Pending || l.Idle || l.Lagging {
+			fmt.Println(l.VertexName, l.Subtask, l.Lag, l.Pending, l.Idle)
+		}
+	}
+}
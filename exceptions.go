@@ -0,0 +1,163 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type ExceptionsResp struct {
+	RootException string          `json:"root-exception"`
+	Timestamp     int64           `json:"timestamp"`
+	AllExceptions []TaskException `json:"all-exceptions"`
+	Truncated     bool            `json:"truncated"`
+
+	ExceptionHistory ExceptionHistory `json:"exceptionHistory"`
+}
+
+type TaskException struct {
+	Exception     string `json:"exception"`
+	Task          string `json:"task"`
+	Location      string `json:"location"`
+	Timestamp     int64  `json:"timestamp"`
+	TaskManagerID string `json:"taskManagerId"`
+}
+
+type ExceptionHistory struct {
+	Entries   []ExceptionEntry `json:"entries"`
+	Truncated bool             `json:"truncated"`
+}
+
+type ExceptionEntry struct {
+	ExceptionName string `json:"exceptionName"`
+	Stacktrace    string `json:"stacktrace"`
+	Timestamp     int64  `json:"timestamp"`
+
+	// TaskName, Location and TaskManagerID are empty for
+	// failures which are not caused by a task. Flink
+	// versions since 1.17 report Location as Endpoint.
+	TaskName      string `json:"taskName"`
+	Location      string `json:"location"`
+	Endpoint      string `json:"endpoint"`
+	TaskManagerID string `json:"taskManagerId"`
+
+	FailureLabels map[string]string `json:"failureLabels"`
+
+	// ConcurrentExceptions lists the failures which happened
+	// together with this one and were handled in the same
+	// restart.
+	ConcurrentExceptions []ExceptionEntry `json:"concurrentExceptions"`
+}
+
+// Exceptions returns the exceptions of a job, including the
+// history of failures which caused restarts. maxExceptions
+// limits the number of history entries; 0 uses the server
+// default.
+func (c *Client) Exceptions(jobID string, maxExceptions int) (ExceptionsResp, error) {
+	return c.ExceptionsCtx(context.Background(), jobID, maxExceptions)
+}
+
+// ExceptionsCtx is like Exceptions but uses ctx to cancel
+// the request.
+func (c *Client) ExceptionsCtx(ctx context.Context, jobID string, maxExceptions int) (ExceptionsResp, error) {
+	var r ExceptionsResp
+	uri := fmt.Sprintf("/jobs/%s/exceptions", jobID)
+	req, err := http.NewRequestWithContext(
+		ctx,
+		"GET",
+		c.url(uri),
+		nil,
+	)
+	if err != nil {
+		return r, err
+	}
+	if maxExceptions > 0 {
+		q := req.URL.Query()
+		q.Add("maxExceptions", strconv.Itoa(maxExceptions))
+		req.URL.RawQuery = q.Encode()
+	}
+	b, err := c.client.Do(req)
+	if err != nil {
+		return r, err
+	}
+	err = json.Unmarshal(b, &r)
+	return r, err
+}
+
+type ExceptionGroup struct {
+	// Class is the exception class, TopFrame the first
+	// frame of its stack trace.
+	Class    string
+	TopFrame string
+
+	Count int
+
+	// First and Last are the timestamps of the oldest and
+	// the newest exception of the group.
+	First int64
+	Last  int64
+
+	// Latest is the newest exception of the group.
+	Latest ExceptionEntry
+}
+
+// GroupExceptions groups exception history entries by
+// exception class and top stack frame, most frequent group
+// first, which shows the failure dominating a restart loop.
+// Concurrent exceptions are not counted.
+func GroupExceptions(entries []ExceptionEntry) []ExceptionGroup {
+	type key struct{ class, frame string }
+	groups := map[key]*ExceptionGroup{}
+	var order []key
+	for _, e := range entries {
+		k := key{e.ExceptionName, topFrame(e.Stacktrace)}
+		g, ok := groups[k]
+		if !ok {
+			g = &ExceptionGroup{
+				Class:    k.class,
+				TopFrame: k.frame,
+				First:    e.Timestamp,
+				Last:     e.Timestamp,
+				Latest:   e,
+			}
+			groups[k] = g
+			order = append(order, k)
+		}
+		g.Count++
+		if e.Timestamp < g.First {
+			g.First = e.Timestamp
+		}
+		if e.Timestamp > g.Last {
+			g.Last = e.Timestamp
+			g.Latest = e
+		}
+	}
+
+	r := make([]ExceptionGroup, 0, len(order))
+	for _, k := range order {
+		r = append(r, *groups[k])
+	}
+	sort.SliceStable(r, func(i, j int) bool {
+		if r[i].Count != r[j].Count {
+			return r[i].Count > r[j].Count
+		}
+		return r[i].Last > r[j].Last
+	})
+	return r
+}
+
+// topFrame returns the first "at ..." line of a Java stack
+// trace, without the "at " prefix.
+func topFrame(stacktrace string) string {
+	for _, line := range strings.Split(stacktrace, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "at ") {
+			return strings.TrimPrefix(line, "at ")
+		}
+	}
+	return ""
+}
@@ -0,0 +1,62 @@
+package api
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGroupExceptions(t *testing.T) {
+	const (
+		npe     = "java.lang.NullPointerException\n\tat com.example.Parser.parse(Parser.java:42)\n\tat com.example.Job.map(Job.java:10)"
+		npeMap  = "java.lang.NullPointerException\n\tat com.example.Job.map(Job.java:12)"
+		timeout = "java.util.concurrent.TimeoutException: Heartbeat of TaskManager timed out.\n\tat org.apache.flink.runtime.jobmaster.JobMaster$TaskManagerHeartbeatListener.notifyHeartbeatTimeout(JobMaster.java:1407)"
+	)
+	entries := []ExceptionEntry{
+		{ExceptionName: "java.lang.NullPointerException", Stacktrace: npe, Timestamp: 300, TaskName: "a"},
+		{ExceptionName: "java.util.concurrent.TimeoutException", Stacktrace: timeout, Timestamp: 500},
+		{ExceptionName: "java.lang.NullPointerException", Stacktrace: npe, Timestamp: 100, TaskName: "b"},
+		{ExceptionName: "java.lang.NullPointerException", Stacktrace: npeMap, Timestamp: 200},
+		{ExceptionName: "java.util.concurrent.TimeoutException", Stacktrace: timeout, Timestamp: 400},
+		{ExceptionName: "java.lang.NullPointerException", Stacktrace: npe, Timestamp: 250, TaskName: "c"},
+		{ExceptionName: "java.lang.IllegalStateException", Timestamp: 600},
+	}
+
+	got := GroupExceptions(entries)
+	want := []ExceptionGroup{
+		{
+			Class:    "java.lang.NullPointerException",
+			TopFrame: "com.example.Parser.parse(Parser.java:42)",
+			Count:    3,
+			First:    100,
+			Last:     300,
+			Latest:   entries[0],
+		},
+		{
+			Class:    "java.util.concurrent.TimeoutException",
+			TopFrame: "org.apache.flink.runtime.jobmaster.JobMaster$TaskManagerHeartbeatListener.notifyHeartbeatTimeout(JobMaster.java:1407)",
+			Count:    2,
+			First:    400,
+			Last:     500,
+			Latest:   entries[1],
+		},
+		// Groups of the same size are ordered newest first.
+		{
+			Class:  "java.lang.IllegalStateException",
+			Count:  1,
+			First:  600,
+			Last:   600,
+			Latest: entries[6],
+		},
+		{
+			Class:    "java.lang.NullPointerException",
+			TopFrame: "com.example.Job.map(Job.java:12)",
+			Count:    1,
+			First:    200,
+			Last:     200,
+			Latest:   entries[3],
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GroupExceptions =\n%+v\nwant\n%+v", got, want)
+	}
+}
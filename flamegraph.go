@@ -0,0 +1,150 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+type FlameGraphType string
+
+const (
+	// FlameGraphFull samples threads in any state, mixing
+	// on-CPU and off-CPU time.
+	FlameGraphFull   FlameGraphType = "full"
+	FlameGraphOnCPU  FlameGraphType = "on_cpu"
+	FlameGraphOffCPU FlameGraphType = "off_cpu"
+)
+
+var (
+	// ErrFlameGraphDisabled is returned when flame graphs are
+	// disabled on the cluster, see 'rest.flamegraph.enabled'.
+	ErrFlameGraphDisabled = errors.New("flink: flame graphs are disabled")
+
+	// ErrFlameGraphTerminated is returned when the vertex is
+	// not running, so it cannot be sampled.
+	ErrFlameGraphTerminated = errors.New("flink: vertex is not running")
+)
+
+// Special end timestamps Flink sends instead of a flame
+// graph.
+const (
+	flameGraphDisabled   = -1
+	flameGraphTerminated = -2
+)
+
+type FlameGraphResp struct {
+	EndTimestamp int64           `json:"endTimestamp"`
+	Data         *FlameGraphNode `json:"data"`
+}
+
+type FlameGraphNode struct {
+	Name string `json:"name"`
+
+	// Value is the number of samples of this frame and
+	// all frames called from it.
+	Value    int64            `json:"value"`
+	Children []FlameGraphNode `json:"children"`
+}
+
+// flameGraphTimeout limits how long FlameGraph waits for
+// Flink to sample a vertex.
+const flameGraphTimeout = time.Minute
+
+// FlameGraph returns the flame graph of a vertex. While
+// Flink is still sampling the vertex, it is polled again
+// for up to a minute.
+func (c *Client) FlameGraph(jobID string, vertexID string, typ FlameGraphType) (FlameGraphResp, error) {
+	return c.FlameGraphCtx(context.Background(), jobID, vertexID, typ)
+}
+
+// FlameGraphCtx is like FlameGraph but uses ctx to cancel
+// the requests and to stop polling.
+func (c *Client) FlameGraphCtx(ctx context.Context, jobID string, vertexID string, typ FlameGraphType) (FlameGraphResp, error) {
+	var r FlameGraphResp
+	err := poll(ctx, WaitOpts{Timeout: flameGraphTimeout}, func(ctx context.Context) (bool, error) {
+		var err error
+		r, err = c.flameGraph(ctx, jobID, vertexID, typ)
+		if err != nil {
+			return false, err
+		}
+		if r.Data != nil {
+			return true, nil
+		}
+		switch r.EndTimestamp {
+		case flameGraphDisabled:
+			return false, ErrFlameGraphDisabled
+		case flameGraphTerminated:
+			return false, ErrFlameGraphTerminated
+		}
+		return false, nil
+	})
+	if errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil {
+		err = fmt.Errorf("flame graph of vertex %s not sampled after %s: %w", vertexID, flameGraphTimeout, err)
+	}
+	return r, err
+}
+
+func (c *Client) flameGraph(ctx context.Context, jobID string, vertexID string, typ FlameGraphType) (FlameGraphResp, error) {
+	var r FlameGraphResp
+	uri := fmt.Sprintf("/jobs/%s/vertices/%s/flamegraph", jobID, vertexID)
+	req, err := http.NewRequestWithContext(
+		ctx,
+		"GET",
+		c.url(uri),
+		nil,
+	)
+	if err != nil {
+		return r, err
+	}
+	if typ != "" {
+		q := req.URL.Query()
+		q.Add("type", string(typ))
+		req.URL.RawQuery = q.Encode()
+	}
+	b, err := c.client.Do(req)
+	if err != nil {
+		return r, err
+	}
+	err = json.Unmarshal(b, &r)
+	return r, err
+}
+
+// WriteFolded writes the flame graph in the folded stack
+// format understood by flamegraph.pl and similar tools: one
+// line per stack, frames separated by ';', followed by the
+// number of samples. The synthetic root frame is omitted.
+func (r FlameGraphResp) WriteFolded(w io.Writer) error {
+	if r.Data == nil {
+		return nil
+	}
+	bw := bufio.NewWriter(w)
+	for _, child := range r.Data.Children {
+		writeFolded(bw, nil, child)
+	}
+	return bw.Flush()
+}
+
+func writeFolded(w *bufio.Writer, stack []string, node FlameGraphNode) {
+	stack = append(stack, foldedFrame(node.Name))
+	self := node.Value
+	for _, child := range node.Children {
+		self -= child.Value
+		writeFolded(w, stack, child)
+	}
+	if self > 0 {
+		fmt.Fprintf(w, "%s %d\n", strings.Join(stack, ";"), self)
+	}
+}
+
+// foldedFrame escapes the characters which separate frames
+// and stacks in the folded format.
+func foldedFrame(name string) string {
+	return strings.NewReplacer(";", ":", "\n", " ").Replace(name)
+}
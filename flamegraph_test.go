@@ -0,0 +1,52 @@
+package api
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWriteFolded(t *testing.T) {
+	r := FlameGraphResp{
+		Data: &FlameGraphNode{
+			Name:  "root",
+			Value: 10,
+			Children: []FlameGraphNode{
+				{
+					Name:  "java.lang.Thread.run:833",
+					Value: 9,
+					Children: []FlameGraphNode{
+						{
+							Name:  "com.example.Job.map:12",
+							Value: 6,
+							Children: []FlameGraphNode{
+								{Name: "com.example.Parser.parse:42", Value: 4},
+								{Name: "scala.Function1;apply\nlambda", Value: 1},
+							},
+						},
+						{Name: "java.lang.Object.wait", Value: 2},
+					},
+				},
+				{Name: "GC", Value: 1},
+			},
+		},
+	}
+	want := "java.lang.Thread.run:833;com.example.Job.map:12;com.example.Parser.parse:42 4\n" +
+		"java.lang.Thread.run:833;com.example.Job.map:12;scala.Function1:apply lambda 1\n" +
+		"java.lang.Thread.run:833;com.example.Job.map:12 1\n" +
+		"java.lang.Thread.run:833;java.lang.Object.wait 2\n" +
+		"java.lang.Thread.run:833 1\n" +
+		"GC 1\n"
+
+	var b bytes.Buffer
+	if err := r.WriteFolded(&b); err != nil {
+		t.Fatal(err)
+	}
+	if b.String() != want {
+		t.Errorf("WriteFolded =\n%s\nwant\n%s", b.String(), want)
+	}
+
+	b.Reset()
+	if err := (FlameGraphResp{}).WriteFolded(&b); err != nil || b.Len() != 0 {
+		t.Errorf("WriteFolded without data = %q, %v, want nothing", b.String(), err)
+	}
+}
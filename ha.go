@@ -0,0 +1,284 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultEndpointCooldown is how long an endpoint which
+// failed is avoided before it is tried again.
+const defaultEndpointCooldown = 30 * time.Second
+
+// WithEndpoints adds further job manager addresses of a high
+// availability cluster. Requests go to one live endpoint and
+// fail over to the next one on connection errors or while a
+// job manager is not able to serve requests because of a
+// leader election.
+func WithEndpoints(addrs ...string) Option {
+	return func(o *options) error {
+		for _, addr := range addrs {
+			if addr == "" {
+				return errors.New("api: empty endpoint address")
+			}
+		}
+		o.endpoints = append(o.endpoints, addrs...)
+		return nil
+	}
+}
+
+// WithEndpointCooldown sets how long an endpoint which
+// failed is avoided before it is tried again.
+func WithEndpointCooldown(d time.Duration) Option {
+	return func(o *options) error {
+		if d < 0 {
+			return fmt.Errorf("api: negative cooldown %s", d)
+		}
+		o.cooldown = d
+		return nil
+	}
+}
+
+// CallInfo reports how a call was served, see WithCallInfo.
+type CallInfo struct {
+	// Endpoint is the host:port of the job manager which
+	// served the last attempt of the call.
+	Endpoint string
+
+	// Attempts is the number of requests sent, including
+	// retries and failovers.
+	Attempts int
+}
+
+type callInfoKey struct{}
+
+// WithCallInfo returns a context which makes a Ctx method
+// fill in info once it returns. info must not be shared by
+// concurrent calls.
+func WithCallInfo(ctx context.Context, info *CallInfo) context.Context {
+	return context.WithValue(ctx, callInfoKey{}, info)
+}
+
+func callInfoFrom(ctx context.Context) *CallInfo {
+	info, _ := ctx.Value(callInfoKey{}).(*CallInfo)
+	return info
+}
+
+type endpoint struct {
+	addr   string
+	scheme string
+	host   string
+
+	downUntil time.Time
+}
+
+func (ep *endpoint) apply(req *http.Request) {
+	req.URL.Scheme = ep.scheme
+	req.URL.Host = ep.host
+	req.Host = ep.host
+}
+
+type endpointPool struct {
+	cooldown time.Duration
+
+	mu        sync.Mutex
+	endpoints []*endpoint
+	current   int
+}
+
+func newEndpointPool(addrs []string, scheme string, cooldown time.Duration) (*endpointPool, error) {
+	if cooldown == 0 {
+		cooldown = defaultEndpointCooldown
+	}
+	p := &endpointPool{cooldown: cooldown}
+	for _, addr := range addrs {
+		raw := addr
+		if !strings.HasPrefix(raw, "http") {
+			raw = scheme + "://" + raw
+		}
+		u, err := url.Parse(raw)
+		if err != nil {
+			return nil, fmt.Errorf("api: invalid endpoint %q: %w", addr, err)
+		}
+		p.endpoints = append(p.endpoints, &endpoint{
+			addr:   addr,
+			scheme: u.Scheme,
+			host:   u.Host,
+		})
+	}
+	return p, nil
+}
+
+// pick returns the endpoint requests should go to: the
+// current one if it is live, else the next live one. When
+// all endpoints are down, the one which failed first is
+// tried again.
+func (p *endpointPool) pick() *endpoint {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := time.Now()
+	for i := range p.endpoints {
+		n := (p.current + i) % len(p.endpoints)
+		if now.After(p.endpoints[n].downUntil) {
+			p.current = n
+			return p.endpoints[n]
+		}
+	}
+	oldest := p.current
+	for n, ep := range p.endpoints {
+		if ep.downUntil.Before(p.endpoints[oldest].downUntil) {
+			oldest = n
+		}
+	}
+	p.current = oldest
+	return p.endpoints[oldest]
+}
+
+func (p *endpointPool) markDown(ep *endpoint) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	ep.downUntil = time.Now().Add(p.cooldown)
+}
+
+func (p *endpointPool) markUp(ep *endpoint) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	ep.downUntil = time.Time{}
+}
+
+// failover sends req to a live endpoint and moves on to the
+// next endpoint when the current one cannot serve it. Every
+// endpoint is tried at most once.
+func (c *httpClient) failover(req *http.Request) (*http.Response, error) {
+	if len(c.endpoints.endpoints) < 2 {
+		return c.attempt(req)
+	}
+	r := req
+	for tried := 1; ; tried++ {
+		ep := c.endpoints.pick()
+		r = r.Clone(r.Context())
+		ep.apply(r)
+		resp, err := c.attempt(r)
+		if !shouldFailover(req, resp, err) {
+			if err == nil {
+				c.endpoints.markUp(ep)
+			}
+			return resp, err
+		}
+		c.endpoints.markDown(ep)
+		if tried >= len(c.endpoints.endpoints) || !canRewind(req) {
+			return resp, err
+		}
+		if resp != nil {
+			drain(resp)
+		}
+		if r, err = rewind(req); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// shouldFailover reports whether a request which ended with
+// resp or err should be sent to another endpoint. Requests
+// which might have been processed are only resent when they
+// are idempotent.
+func shouldFailover(req *http.Request, resp *http.Response, err error) bool {
+	if err != nil {
+		if !isConnError(err) {
+			return false
+		}
+		var opErr *net.OpError
+		if errors.As(err, &opErr) && opErr.Op == "dial" {
+			return true
+		}
+		return req.Method == http.MethodGet || req.Method == http.MethodHead
+	}
+	if resp.StatusCode/100 == 3 {
+		return true
+	}
+	return resp.StatusCode == http.StatusServiceUnavailable && mentionsLeader(resp)
+}
+
+// mentionsLeader reports whether the body of resp refers to
+// the job manager leadership, like the answer Flink sends
+// during a leader election. The body is left readable.
+func mentionsLeader(resp *http.Response) bool {
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+	return err == nil && bytes.Contains(bytes.ToLower(body), []byte("leader"))
+}
+
+// EndpointStatus is the result of a health check of one job
+// manager endpoint.
+type EndpointStatus struct {
+	Addr    string
+	Healthy bool
+	Latency time.Duration
+	Err     error
+}
+
+// CheckEndpoints probes every configured job manager with a
+// request to '/config'. Healthy endpoints are used for
+// further requests, failing ones are avoided for a while.
+func (c *Client) CheckEndpoints(ctx context.Context) []EndpointStatus {
+	pool := c.client.endpoints
+	statuses := make([]EndpointStatus, len(pool.endpoints))
+	var wg sync.WaitGroup
+	for i, ep := range pool.endpoints {
+		wg.Add(1)
+		go func(i int, ep *endpoint) {
+			defer wg.Done()
+			statuses[i] = c.checkEndpoint(ctx, ep)
+			if statuses[i].Healthy {
+				pool.markUp(ep)
+			} else {
+				pool.markDown(ep)
+			}
+		}(i, ep)
+	}
+	wg.Wait()
+	return statuses
+}
+
+func (c *Client) checkEndpoint(ctx context.Context, ep *endpoint) EndpointStatus {
+	s := EndpointStatus{Addr: ep.addr}
+	if c.client.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.client.timeout)
+		defer cancel()
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", c.url("/config"), nil)
+	if err != nil {
+		s.Err = err
+		return s
+	}
+	ep.apply(req)
+	start := time.Now()
+	resp, err := c.client.attempt(req)
+	s.Latency = time.Since(start)
+	if err != nil {
+		s.Err = err
+		return s
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		s.Err = err
+		return s
+	}
+	if resp.StatusCode/100 != 2 {
+		s.Err = newAPIError(req, resp, body)
+		return s
+	}
+	s.Healthy = true
+	return s
+}
@@ -0,0 +1,171 @@
+package api
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const leaderElectionBody = `{"errors":["Service temporarily unavailable due to an ongoing leader election. Please refresh."]}`
+
+// closedAddr returns the address of a port nobody listens
+// on, so that dialing it fails.
+func closedAddr(t *testing.T) string {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := l.Addr().String()
+	l.Close()
+	return "http://" + addr
+}
+
+func hostOf(t *testing.T, rawURL string) string {
+	t.Helper()
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return u.Host
+}
+
+func TestFailover(t *testing.T) {
+	standby := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte(leaderElectionBody))
+	}))
+	defer standby.Close()
+	leader := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"jobs":[],"jobid":"j"}`))
+	}))
+	defer leader.Close()
+
+	tests := []struct {
+		name  string
+		first string
+		call  func(ctx context.Context, c *Client) error
+	}{
+		{
+			name:  "GET during leader election",
+			first: standby.URL,
+			call: func(ctx context.Context, c *Client) error {
+				_, err := c.JobsCtx(ctx)
+				return err
+			},
+		},
+		{
+			name:  "POST during leader election",
+			first: standby.URL,
+			call: func(ctx context.Context, c *Client) error {
+				_, err := c.RunJarCtx(ctx, RunOpts{JarID: "a.jar"})
+				return err
+			},
+		},
+		{
+			name:  "GET on dial error",
+			first: closedAddr(t),
+			call: func(ctx context.Context, c *Client) error {
+				_, err := c.JobsCtx(ctx)
+				return err
+			},
+		},
+		{
+			name:  "POST on dial error",
+			first: closedAddr(t),
+			call: func(ctx context.Context, c *Client) error {
+				_, err := c.RunJarCtx(ctx, RunOpts{JarID: "a.jar"})
+				return err
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := New(tt.first, WithEndpoints(leader.URL), WithRetryPolicy(RetryPolicy{}))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var info CallInfo
+			if err := tt.call(WithCallInfo(context.Background(), &info), c); err != nil {
+				t.Fatal(err)
+			}
+			if want := hostOf(t, leader.URL); info.Endpoint != want {
+				t.Errorf("served by %s, want %s", info.Endpoint, want)
+			}
+			if info.Attempts != 2 {
+				t.Errorf("got %d attempts, want 2", info.Attempts)
+			}
+
+			// The failed endpoint is avoided afterwards.
+			info = CallInfo{}
+			if err := tt.call(WithCallInfo(context.Background(), &info), c); err != nil {
+				t.Fatal(err)
+			}
+			if info.Attempts != 1 {
+				t.Errorf("got %d attempts after failover, want 1", info.Attempts)
+			}
+		})
+	}
+}
+
+func TestNoFailoverOnOtherErrors(t *testing.T) {
+	var hits int64
+	first := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt64(&hits, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte(`{"errors":["overloaded"]}`))
+	}))
+	defer first.Close()
+	second := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request failed over to the second endpoint")
+	}))
+	defer second.Close()
+
+	c, err := New(first.URL, WithEndpoints(second.URL), WithRetryPolicy(RetryPolicy{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Jobs(); !IsStatus(err, http.StatusServiceUnavailable) {
+		t.Fatalf("err = %v, want a 503 APIError", err)
+	}
+	if n := atomic.LoadInt64(&hits); n != 1 {
+		t.Errorf("got %d requests, want 1", n)
+	}
+}
+
+func TestCheckEndpointsTimeout(t *testing.T) {
+	release := make(chan struct{})
+	hung := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}))
+	defer hung.Close()
+	defer close(release)
+	healthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[]`))
+	}))
+	defer healthy.Close()
+
+	c, err := New(hung.URL, WithEndpoints(healthy.URL), WithTimeout(100*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	statuses := c.CheckEndpoints(context.Background())
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("CheckEndpoints took %s, want the 100ms timeout to apply", d)
+	}
+	if statuses[0].Healthy || statuses[0].Err == nil {
+		t.Errorf("hung endpoint reported %+v, want unhealthy with an error", statuses[0])
+	}
+	if !statuses[1].Healthy {
+		t.Errorf("healthy endpoint reported %+v", statuses[1])
+	}
+}
@@ -2,14 +2,12 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"mime/multipart"
 	"net/http"
 	"os"
-	"path/filepath"
-	"strconv"
 	"strings"
 )
 
@@ -18,27 +16,56 @@ type Client struct {
 	// Addr reprents flink job manager server address
 	Addr string
 
-	client *httpClient
+	scheme   string
+	basePath string
+	client   *httpClient
 }
 
-// New returns a flink client
-func New(addr string) (*Client, error) {
+// New returns a flink client. The client can be customized
+// with opts, e.g. to talk to a TLS enabled job manager.
+func New(addr string, opts ...Option) (*Client, error) {
+	var o options
+	for _, opt := range opts {
+		if err := opt(&o); err != nil {
+			return nil, err
+		}
+	}
+	scheme := "http"
+	if o.usesTLS() {
+		scheme = "https"
+	}
+	pool, err := newEndpointPool(append([]string{addr}, o.endpoints...), scheme, o.cooldown)
+	if err != nil {
+		return nil, err
+	}
+	hc, err := newHttpClient(o, pool)
+	if err != nil {
+		return nil, err
+	}
 	return &Client{
-		Addr:   addr,
-		client: newHttpClient(),
+		Addr:     addr,
+		scheme:   scheme,
+		basePath: o.basePath,
+		client:   hc,
 	}, nil
 }
 
 func (c *Client) url(path string) string {
 	if strings.HasPrefix(c.Addr, "http") {
-		return fmt.Sprintf("%s%s", c.Addr, path)
+		return fmt.Sprintf("%s%s%s", c.Addr, c.basePath, path)
 	}
-	return fmt.Sprintf("http://%s%s", c.Addr, path)
+	return fmt.Sprintf("%s://%s%s%s", c.scheme, c.Addr, c.basePath, path)
 }
 
 // Shutdown shutdown the flink cluster
 func (c *Client) Shutdown() error {
-	req, err := http.NewRequest("DELETE", c.url("/cluster"), nil)
+	return c.ShutdownCtx(context.Background())
+}
+
+// ShutdownCtx is like Shutdown but uses ctx to cancel the
+// request.
+func (c *Client) ShutdownCtx(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, "DELETE", c.url("/cluster"), nil)
 	if err != nil {
 		return err
 	}
@@ -60,8 +87,14 @@ type features struct {
 
 // Config returns the configuration of the WebUI
 func (c *Client) Config() (ConfigResp, error) {
+	return c.ConfigCtx(context.Background())
+}
+
+// ConfigCtx is like Config but uses ctx to cancel the
+// request.
+func (c *Client) ConfigCtx(ctx context.Context) (ConfigResp, error) {
 	var r ConfigResp
-	req, err := http.NewRequest("GET", c.url("/config"), nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", c.url("/config"), nil)
 	if err != nil {
 		return r, err
 	}
@@ -80,24 +113,24 @@ type UploadResp struct {
 
 // Upload uploads jar file
 func (c *Client) UploadJar(fpath string) (UploadResp, error) {
+	return c.UploadJarCtx(context.Background(), fpath)
+}
+
+// UploadJarCtx is like UploadJar but uses ctx to cancel the
+// request. The jar is streamed to the server, so canceling
+// ctx also stops an upload which is still in progress.
+func (c *Client) UploadJarCtx(ctx context.Context, fpath string) (UploadResp, error) {
 	var r UploadResp
-	file, err := os.Open(fpath)
-	if err != nil {
+	if _, err := os.Stat(fpath); err != nil {
 		return r, err
 	}
-	defer file.Close()
-
-	body := &bytes.Buffer{}
-	writer := multipart.NewWriter(body)
-	part, err := writer.CreateFormFile("jarfile", filepath.Base(file.Name()))
-	io.Copy(part, file)
-	writer.Close()
 
-	req, err := http.NewRequest("POST", c.url("/jars/upload"), body)
+	req, err := newMultipartRequest(ctx, c.url("/jars/upload"), func(w *multipart.Writer) error {
+		return writeFormFile(w, "jarfile", fpath)
+	})
 	if err != nil {
 		return r, err
 	}
-	req.Header.Add("Content-Type", writer.FormDataContentType())
 	b, err := c.client.Do(req)
 	if err != nil {
 		return r, err
@@ -126,8 +159,13 @@ type Entry struct {
 // Jars eturns a list of all jars previously uploaded
 // via '/jars/upload'
 func (c *Client) Jars() (JarsResp, error) {
+	return c.JarsCtx(context.Background())
+}
+
+// JarsCtx is like Jars but uses ctx to cancel the request.
+func (c *Client) JarsCtx(ctx context.Context) (JarsResp, error) {
 	var r JarsResp
-	req, err := http.NewRequest("GET", c.url("/jars"), nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", c.url("/jars"), nil)
 	if err != nil {
 		return r, err
 	}
@@ -141,8 +179,14 @@ func (c *Client) Jars() (JarsResp, error) {
 
 // DeleteJar deletes a jar file
 func (c *Client) DeleteJar(jarid string) error {
+	return c.DeleteJarCtx(context.Background(), jarid)
+}
+
+// DeleteJarCtx is like DeleteJar but uses ctx to cancel the
+// request.
+func (c *Client) DeleteJarCtx(ctx context.Context, jarid string) error {
 	uri := fmt.Sprintf("/jars/%s", jarid)
-	req, err := http.NewRequest("DELETE", c.url(uri), nil)
+	req, err := http.NewRequestWithContext(ctx, "DELETE", c.url(uri), nil)
 	if err != nil {
 		return err
 	}
@@ -180,9 +224,15 @@ type Input struct {
 // in a jar previously uploaded via '/jars/upload'.
 // Todo: support more args.
 func (c *Client) PlanJar(jarid string) (PlanResp, error) {
+	return c.PlanJarCtx(context.Background(), jarid)
+}
+
+// PlanJarCtx is like PlanJar but uses ctx to cancel the
+// request.
+func (c *Client) PlanJarCtx(ctx context.Context, jarid string) (PlanResp, error) {
 	var r PlanResp
 	uri := fmt.Sprintf("/jars/%s/plan", jarid)
-	req, err := http.NewRequest("GET", c.url(uri), nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", c.url(uri), nil)
 	if err != nil {
 		return r, err
 	}
@@ -227,32 +277,75 @@ type RunOpts struct {
 	// Parallelism (optional): Positive integer value that
 	// specifies the desired parallelism for the job.
 	Parallelism int
+
+	// JobID (optional): 32-character hexadecimal string
+	// which is used as the ID of the job instead of a
+	// random one.
+	JobID string
+
+	// RestoreMode (optional): how the savepoint at
+	// SavepointPath is restored. Defaults to the cluster
+	// setting.
+	RestoreMode RestoreMode
+
+	// FlinkConfiguration (optional): configuration options
+	// which override the cluster configuration for this
+	// job.
+	FlinkConfiguration map[string]string
 }
 
+// RestoreMode describes how a job takes ownership of the
+// savepoint it is restored from.
+type RestoreMode string
+
+const (
+	RestoreModeClaim   RestoreMode = "CLAIM"
+	RestoreModeNoClaim RestoreMode = "NO_CLAIM"
+	RestoreModeLegacy  RestoreMode = "LEGACY"
+)
+
 // RunJar submits a job by running a jar previously
 // uploaded via '/jars/upload'.
 func (c *Client) RunJar(opts RunOpts) (RunResp, error) {
+	return c.RunJarCtx(context.Background(), opts)
+}
+
+// RunJarCtx is like RunJar but uses ctx to cancel the
+// request.
+func (c *Client) RunJarCtx(ctx context.Context, opts RunOpts) (RunResp, error) {
 	var r RunResp
-	uri := fmt.Sprintf("/jars/%s/run", opts.JarID)
-	req, err := http.NewRequest("POST", c.url(uri), nil)
-	q := req.URL.Query()
-	if opts.SavepointPath != "" {
-		q.Add("savepointPath", opts.SavepointPath)
-		q.Add("allowNonRestoredState", strconv.FormatBool(opts.AllowNonRestoredState))
-	}
-	for _, arg := range opts.ProgramArgsList {
-		q.Add("programArgsList", arg)
+
+	type RunJarReq struct {
+		EntryClass            string            `json:"entryClass,omitempty"`
+		ProgramArgsList       []string          `json:"programArgsList,omitempty"`
+		Parallelism           int               `json:"parallelism,omitempty"`
+		JobID                 string            `json:"jobId,omitempty"`
+		SavepointPath         string            `json:"savepointPath,omitempty"`
+		AllowNonRestoredState bool              `json:"allowNonRestoredState,omitempty"`
+		RestoreMode           RestoreMode       `json:"restoreMode,omitempty"`
+		FlinkConfiguration    map[string]string `json:"flinkConfiguration,omitempty"`
 	}
-	if opts.EntryClass != "" {
-		q.Add("entry-class", opts.EntryClass)
+
+	d := RunJarReq{
+		EntryClass:         opts.EntryClass,
+		ProgramArgsList:    opts.ProgramArgsList,
+		Parallelism:        opts.Parallelism,
+		JobID:              opts.JobID,
+		SavepointPath:      opts.SavepointPath,
+		RestoreMode:        opts.RestoreMode,
+		FlinkConfiguration: opts.FlinkConfiguration,
 	}
-	if opts.Parallelism > 0 {
-		q.Add("parallelism", strconv.Itoa(opts.Parallelism))
+	if opts.SavepointPath != "" {
+		d.AllowNonRestoredState = opts.AllowNonRestoredState
 	}
-	req.URL.RawQuery = q.Encode()
+	data := new(bytes.Buffer)
+	json.NewEncoder(data).Encode(d)
+	uri := fmt.Sprintf("/jars/%s/run", opts.JarID)
+	req, err := http.NewRequestWithContext(ctx, "POST", c.url(uri), data)
 	if err != nil {
 		return r, err
 	}
+	req.Header.Set("Content-Type", "application/json")
 	b, err := c.client.Do(req)
 	if err != nil {
 		return r, err
@@ -2,10 +2,15 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"mime/multipart"
 	"net/http"
-	"strings"
+	"net/textproto"
+	"os"
+	"path"
+	"path/filepath"
 )
 
 type KV struct {
@@ -16,8 +21,15 @@ type KV struct {
 // JobManagerConfig returns the cluster configuration of
 // job manager server.
 func (c *Client) JobManagerConfig() ([]KV, error) {
+	return c.JobManagerConfigCtx(context.Background())
+}
+
+// JobManagerConfigCtx is like JobManagerConfig but uses ctx
+// to cancel the request.
+func (c *Client) JobManagerConfigCtx(ctx context.Context) ([]KV, error) {
 	var r []KV
-	req, err := http.NewRequest(
+	req, err := http.NewRequestWithContext(
+		ctx,
 		"GET",
 		c.url("/jobmanager/config"),
 		nil,
@@ -40,8 +52,15 @@ type Metric struct {
 // JobManagerMetrics provides access to job manager
 // metrics.
 func (c *Client) JobManagerMetrics() ([]Metric, error) {
+	return c.JobManagerMetricsCtx(context.Background())
+}
+
+// JobManagerMetricsCtx is like JobManagerMetrics but uses
+// ctx to cancel the request.
+func (c *Client) JobManagerMetricsCtx(ctx context.Context) ([]Metric, error) {
 	var r []Metric
-	req, err := http.NewRequest(
+	req, err := http.NewRequestWithContext(
+		ctx,
 		"GET",
 		c.url("/jobmanager/metrics"),
 		nil,
@@ -69,8 +88,14 @@ type Job struct {
 // Jobs returns an overview over all jobs and their
 // current state.
 func (c *Client) Jobs() (JobsResp, error) {
+	return c.JobsCtx(context.Background())
+}
+
+// JobsCtx is like Jobs but uses ctx to cancel the request.
+func (c *Client) JobsCtx(ctx context.Context) (JobsResp, error) {
 	var r JobsResp
-	req, err := http.NewRequest(
+	req, err := http.NewRequestWithContext(
+		ctx,
 		"GET",
 		c.url("/jobs"),
 		nil,
@@ -86,52 +111,118 @@ func (c *Client) Jobs() (JobsResp, error) {
 	return r, err
 }
 
-// SubmitJob submits a job.
-func (c *Client) SubmitJob() error {
-	return fmt.Errorf("not implement")
+type SubmitJobOpts struct {
+	// JobGraphFile: path of the serialized JobGraph.
+	JobGraphFile string
+
+	// JarFiles (optional): paths of the jars the job needs.
+	JarFiles []string
+
+	// Artifacts (optional): files which are registered in
+	// the distributed cache of the job.
+	Artifacts []Artifact
 }
 
-type JobMetricsOpts struct {
-	// Metrics (optional): string values to select
-	// specific metrics.
-	Metrics []string
+type Artifact struct {
+	// EntryName is the name the file is registered under.
+	EntryName string
 
-	// Agg (optional): list of aggregation modes which
-	// should be calculated. Available aggregations are:
-	// "min, max, sum, avg".
-	Agg []string
+	// File is the path of the file.
+	File string
+}
 
-	// Jobs (optional): job list of 32-character
-	// hexadecimal strings to select specific jobs.
-	Jobs []string
+type SubmitJobResp struct {
+	JobURL string `json:"jobUrl"`
 }
 
-// JobMetrics provides access to aggregated job metrics.
-func (c *Client) JobMetrics(opts JobMetricsOpts) (map[string]interface{}, error) {
-	var r map[string]interface{}
-	req, err := http.NewRequest(
-		"GET",
-		c.url("/jobs/metrics"),
-		nil,
-	)
-	if err != nil {
-		return r, err
+// JobID returns the ID of the submitted job.
+func (r SubmitJobResp) JobID() string {
+	return path.Base(r.JobURL)
+}
+
+// SubmitJob submits a job from a serialized JobGraph. The
+// JobGraph, jars and artifacts are uploaded in a single
+// multipart request.
+func (c *Client) SubmitJob(opts SubmitJobOpts) (SubmitJobResp, error) {
+	return c.SubmitJobCtx(context.Background(), opts)
+}
+
+// SubmitJobCtx is like SubmitJob but uses ctx to cancel the
+// request.
+func (c *Client) SubmitJobCtx(ctx context.Context, opts SubmitJobOpts) (SubmitJobResp, error) {
+	var r SubmitJobResp
+
+	type artifactReq struct {
+		EntryName string `json:"entryName"`
+		FileName  string `json:"fileName"`
 	}
-	q := req.URL.Query()
-	if len(opts.Metrics) > 0 {
-		q.Add("get", strings.Join(opts.Metrics, ","))
+	type SubmitJobReq struct {
+		JobGraphFileName     string        `json:"jobGraphFileName"`
+		JobJarFileNames      []string      `json:"jobJarFileNames"`
+		JobArtifactFileNames []artifactReq `json:"jobArtifactFileNames"`
 	}
 
-	if len(opts.Agg) > 0 {
-		q.Add("agg", strings.Join(opts.Agg, ","))
+	if opts.JobGraphFile == "" {
+		return r, fmt.Errorf("job graph file is required")
 	}
-
-	if len(opts.Jobs) > 0 {
-		q.Add("jobs", strings.Join(opts.Jobs, ","))
+	files := []string{opts.JobGraphFile}
+	files = append(files, opts.JarFiles...)
+	for _, a := range opts.Artifacts {
+		files = append(files, a.File)
+	}
+	// Flink refers to uploaded files by their name only.
+	seen := map[string]bool{}
+	for _, f := range files {
+		if _, err := os.Stat(f); err != nil {
+			return r, err
+		}
+		name := filepath.Base(f)
+		if seen[name] {
+			return r, fmt.Errorf("duplicate file name %q", name)
+		}
+		seen[name] = true
 	}
 
-	req.URL.RawQuery = q.Encode()
+	d := SubmitJobReq{
+		JobGraphFileName:     filepath.Base(opts.JobGraphFile),
+		JobJarFileNames:      []string{},
+		JobArtifactFileNames: []artifactReq{},
+	}
+	for _, f := range opts.JarFiles {
+		d.JobJarFileNames = append(d.JobJarFileNames, filepath.Base(f))
+	}
+	for _, a := range opts.Artifacts {
+		d.JobArtifactFileNames = append(d.JobArtifactFileNames, artifactReq{
+			EntryName: a.EntryName,
+			FileName:  filepath.Base(a.File),
+		})
+	}
+	data, err := json.Marshal(d)
+	if err != nil {
+		return r, err
+	}
 
+	req, err := newMultipartRequest(ctx, c.url("/jobs"), func(w *multipart.Writer) error {
+		h := textproto.MIMEHeader{}
+		h.Set("Content-Disposition", `form-data; name="request"`)
+		h.Set("Content-Type", "application/json")
+		part, err := w.CreatePart(h)
+		if err != nil {
+			return err
+		}
+		if _, err := part.Write(data); err != nil {
+			return err
+		}
+		for i, f := range files {
+			if err := writeFormFile(w, fmt.Sprintf("file_%d", i), f); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return r, err
+	}
 	b, err := c.client.Do(req)
 	if err != nil {
 		return r, err
@@ -140,6 +231,38 @@ func (c *Client) JobMetrics(opts JobMetricsOpts) (map[string]interface{}, error)
 	return r, err
 }
 
+type JobMetricsOpts struct {
+	// Metrics (optional): string values to select
+	// specific metrics.
+	Metrics []string
+
+	// Agg (optional): list of aggregation modes which
+	// should be calculated. Available aggregations are:
+	// "min, max, sum, avg".
+	Agg []string
+
+	// Jobs (optional): job list of 32-character
+	// hexadecimal strings to select specific jobs.
+	Jobs []string
+}
+
+// JobMetrics provides access to metrics aggregated over
+// all jobs, or over opts.Jobs. It is a shorthand for Metrics
+// with JobsScope.
+func (c *Client) JobMetrics(opts JobMetricsOpts) ([]MetricValue, error) {
+	return c.JobMetricsCtx(context.Background(), opts)
+}
+
+// JobMetricsCtx is like JobMetrics but uses ctx to cancel
+// the request.
+func (c *Client) JobMetricsCtx(ctx context.Context, opts JobMetricsOpts) ([]MetricValue, error) {
+	return c.MetricsCtx(ctx, JobsScope(), MetricsOpts{
+		Metrics: opts.Metrics,
+		Agg:     opts.Agg,
+		Subset:  opts.Jobs,
+	})
+}
+
 type OverviewResp struct {
 	Jobs []JobOverview `json:"jobs"`
 }
@@ -170,8 +293,15 @@ type Status struct {
 
 // JobsOverview returns an overview over all jobs.
 func (c *Client) JobsOverview() (OverviewResp, error) {
+	return c.JobsOverviewCtx(context.Background())
+}
+
+// JobsOverviewCtx is like JobsOverview but uses ctx to
+// cancel the request.
+func (c *Client) JobsOverviewCtx(ctx context.Context) (OverviewResp, error) {
 	var r OverviewResp
-	req, err := http.NewRequest(
+	req, err := http.NewRequestWithContext(
+		ctx,
 		"GET",
 		c.url("/jobs/overview"),
 		nil,
@@ -231,9 +361,15 @@ type Vertice struct {
 
 // Job returns details of a job.
 func (c *Client) Job(jobID string) (JobResp, error) {
+	return c.JobCtx(context.Background(), jobID)
+}
+
+// JobCtx is like Job but uses ctx to cancel the request.
+func (c *Client) JobCtx(ctx context.Context, jobID string) (JobResp, error) {
 	var r JobResp
 	uri := fmt.Sprintf("/jobs/%s", jobID)
-	req, err := http.NewRequest(
+	req, err := http.NewRequestWithContext(
+		ctx,
 		"GET",
 		c.url(uri),
 		nil,
@@ -251,8 +387,15 @@ func (c *Client) Job(jobID string) (JobResp, error) {
 
 // StopJob terminates a job.
 func (c *Client) StopJob(jobID string) error {
+	return c.StopJobCtx(context.Background(), jobID)
+}
+
+// StopJobCtx is like StopJob but uses ctx to cancel the
+// request.
+func (c *Client) StopJobCtx(ctx context.Context, jobID string) error {
 	uri := fmt.Sprintf("/jobs/%s", jobID)
-	req, err := http.NewRequest(
+	req, err := http.NewRequestWithContext(
+		ctx,
 		"PATCH",
 		c.url(uri),
 		nil,
@@ -264,128 +407,6 @@ func (c *Client) StopJob(jobID string) error {
 	return err
 }
 
-type checkpointsResp struct {
-	Counts  Counts                     `json:"counts"`
-	Summary Summary                    `json:"summary"`
-	Latest  Latest                     `json:"latest"`
-	History []failedCheckpointsStatics `json:"history"`
-}
-
-type Counts struct {
-	Restored   int `json:"restored"`
-	Total      int `json:"total"`
-	InProgress int `json:"in_progress"`
-	Completed  int `json:"completed"`
-	Failed     int `json:"failed"`
-}
-
-type Summary struct {
-	StateSize         statics `json:"state_size"`
-	End2EndDuration   statics `json:"end_to_end_duration"`
-	AlignmentBuffered statics `json:"alignment_buffered"`
-}
-
-type statics struct {
-	Min int `json:"min"`
-	Max int `json:"max"`
-	Avg int `json:"avg"`
-}
-
-type Latest struct {
-	Completed CompletedCheckpointsStatics `json:"completed"`
-	Savepoint savepointsStatics           `json:"savepoint"`
-	Failed    failedCheckpointsStatics    `json:"failed"`
-	Restored  restoredCheckpointsStatics  `json:"restored"`
-}
-
-type CompletedCheckpointsStatics struct {
-	ID                      string                 `json:"id"`
-	Status                  string                 `json:"status"`
-	IsSavepoint             bool                   `json:"is_savepoint"`
-	TriggerTimestamp        int64                  `json:"trigger_timestamp"`
-	LatestAckTimestamp      int64                  `json:"latest_ack_timestamp"`
-	StateSize               int64                  `json:"state_size"`
-	End2EndDuration         int64                  `json:"end_to_end_duration"`
-	AlignmentBuffered       int64                  `json:"alignment_buffered"`
-	NumSubtasks             int64                  `json:"num_subtasks"`
-	NumAcknowledgedSubtasks int64                  `json:"num_acknowledged_subtasks"`
-	Tasks                   TaskCheckpointsStatics `json:"tasks"`
-	ExternalPath            string                 `json:"external_path"`
-	Discarded               bool                   `json:"discarded"`
-}
-
-type savepointsStatics struct {
-	ID                      int                    `json:"id"`
-	Status                  string                 `json:"status"`
-	IsSavepoint             bool                   `json:"is_savepoint"`
-	TriggerTimestamp        int64                  `json:"trigger_timestamp"`
-	LatestAckTimestamp      int64                  `json:"latest_ack_timestamp"`
-	StateSize               int64                  `json:"state_size"`
-	End2EndDuration         int64                  `json:"end_to_end_duration"`
-	AlignmentBuffered       int64                  `json:"alignment_buffered"`
-	NumSubtasks             int64                  `json:"num_subtasks"`
-	NumAcknowledgedSubtasks int64                  `json:"num_acknowledged_subtasks"`
-	Tasks                   TaskCheckpointsStatics `json:"tasks"`
-	ExternalPath            string                 `json:"external_path"`
-	Discarded               bool                   `json:"discarded"`
-}
-type TaskCheckpointsStatics struct {
-	ID     string `json:"id"`
-	Status string `json:"status"`
-
-	LatestAckTimestamp int64 `json:"latest_ack_timestamp"`
-
-	FailureTimestamp int64  `json:"failure_timestamp"`
-	FailureMessage   string `json:"failure_message"`
-
-	StateSize               int64 `json:"state_size"`
-	End2EndDuration         int64 `json:"end_to_end_duration"`
-	AlignmentBuffered       int64 `json:"alignment_buffered"`
-	NumSubtasks             int64 `json:"num_subtasks"`
-	NumAcknowledgedSubtasks int64 `json:"num_acknowledged_subtasks"`
-}
-
-type failedCheckpointsStatics struct {
-	ID                      int64                  `json:"id"`
-	Status                  string                 `json:"status"`
-	IsSavepoint             bool                   `json:"is_savepoint"`
-	TriggerTimestamp        int64                  `json:"trigger_timestamp"`
-	LatestAckTimestamp      int64                  `json:"latest_ack_timestamp"`
-	StateSize               int64                  `json:"state_size"`
-	End2EndDuration         int64                  `json:"end_to_end_duration"`
-	AlignmentBuffered       int64                  `json:"alignment_buffered"`
-	NumSubtasks             int64                  `json:"num_subtasks"`
-	NumAcknowledgedSubtasks int64                  `json:"num_acknowledged_subtasks"`
-	Tasks                   TaskCheckpointsStatics `json:"tasks"`
-}
-
-type restoredCheckpointsStatics struct {
-	ID               int64  `json:"id"`
-	RestoreTimestamp int64  `json:"restore_timestamp"`
-	IsSavepoint      bool   `json:"is_savepoint"`
-	ExternalPath     string `json:"external_path"`
-}
-
-// Checkpoints returns checkpointing statistics for a job.
-func (c *Client) Checkpoints(jobID string) (checkpointsResp, error) {
-	var r checkpointsResp
-	uri := fmt.Sprintf("/jobs/%s/checkpoints", jobID)
-	req, err := http.NewRequest(
-		"GET",
-		c.url(uri),
-		nil,
-	)
-	if err != nil {
-		return r, err
-	}
-	b, err := c.client.Do(req)
-	if err != nil {
-		return r, err
-	}
-	err = json.Unmarshal(b, &r)
-	return r, err
-}
-
 type SavePointsResp struct {
 	RequestID string `json:"request-id"`
 }
@@ -394,6 +415,12 @@ type SavePointsResp struct {
 // job afterwards. This async operation would return a
 // 'triggerid' for further query identifier.
 func (c *Client) SavePoints(jobID string, saveDir string, cancelJob bool) (SavePointsResp, error) {
+	return c.SavePointsCtx(context.Background(), jobID, saveDir, cancelJob)
+}
+
+// SavePointsCtx is like SavePoints but uses ctx to cancel
+// the request.
+func (c *Client) SavePointsCtx(ctx context.Context, jobID string, saveDir string, cancelJob bool) (SavePointsResp, error) {
 	var r SavePointsResp
 
 	type SavePointsReq struct {
@@ -408,7 +435,8 @@ func (c *Client) SavePoints(jobID string, saveDir string, cancelJob bool) (SaveP
 	data := new(bytes.Buffer)
 	json.NewEncoder(data).Encode(d)
 	uri := fmt.Sprintf("/jobs/%s/savepoints", jobID)
-	req, err := http.NewRequest(
+	req, err := http.NewRequestWithContext(
+		ctx,
 		"POST",
 		c.url(uri),
 		data,
@@ -431,15 +459,23 @@ const (
 	SavepointStatusInCompleted SavepointStatusId = "COMPLETED"
 )
 
-type TrackSavepointRespFailureCause struct {
+// FailureCause describes a Java exception reported by
+// Flink, e.g. the cause of a failed job or savepoint.
+type FailureCause struct {
 	Class               string `json:"class"`
 	StackTrace          string `json:"stack-trace"`
 	SerializedThrowable string `json:"serialized-throwable"`
 }
 
+// TrackSavepointRespFailureCause is the former name of
+// FailureCause.
+//
+// Deprecated: use FailureCause.
+type TrackSavepointRespFailureCause = FailureCause
+
 type TrackSavepointRespOperation struct {
-	FailureCause TrackSavepointRespFailureCause `json:"failure-cause"`
-	Location     string                         `json:"location"`
+	FailureCause FailureCause `json:"failure-cause"`
+	Location     string       `json:"location"`
 }
 type TrackSavepointRespStatus struct {
 	Id SavepointStatusId `json:"id"`
@@ -451,10 +487,17 @@ type TrackSavepointResp struct {
 
 // Check the status of triggered savepoint
 func (c *Client) TrackSavepoint(jobID string, triggerId string) (TrackSavepointResp, error) {
+	return c.TrackSavepointCtx(context.Background(), jobID, triggerId)
+}
+
+// TrackSavepointCtx is like TrackSavepoint but uses ctx to
+// cancel the request.
+func (c *Client) TrackSavepointCtx(ctx context.Context, jobID string, triggerId string) (TrackSavepointResp, error) {
 	var r TrackSavepointResp
 
 	uri := fmt.Sprintf("/jobs/%s/savepoints/%s", jobID, triggerId)
-	req, err := http.NewRequest(
+	req, err := http.NewRequestWithContext(
+		ctx,
 		"GET",
 		c.url(uri),
 		nil,
@@ -479,6 +522,12 @@ type StopJobResp struct {
 // any state waiting for timers to fire. This async operation
 // would return a 'triggerid' for further query identifier.
 func (c *Client) StopJobWithSavepoint(jobID string, saveDir string, drain bool) (StopJobResp, error) {
+	return c.StopJobWithSavepointCtx(context.Background(), jobID, saveDir, drain)
+}
+
+// StopJobWithSavepointCtx is like StopJobWithSavepoint but
+// uses ctx to cancel the request.
+func (c *Client) StopJobWithSavepointCtx(ctx context.Context, jobID string, saveDir string, drain bool) (StopJobResp, error) {
 	var r StopJobResp
 	type StopJobReq struct {
 		SaveDir string `json:"targetDirectory"`
@@ -492,7 +541,8 @@ func (c *Client) StopJobWithSavepoint(jobID string, saveDir string, drain bool)
 	data := new(bytes.Buffer)
 	json.NewEncoder(data).Encode(d)
 	uri := fmt.Sprintf("/jobs/%s/stop", jobID)
-	req, err := http.NewRequest(
+	req, err := http.NewRequestWithContext(
+		ctx,
 		"POST",
 		c.url(uri),
 		data,
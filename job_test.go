@@ -0,0 +1,132 @@
+package api
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func writeTempFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestSubmitJob(t *testing.T) {
+	dir := t.TempDir()
+	opts := SubmitJobOpts{
+		JobGraphFile: writeTempFile(t, dir, "job.graph", "graph"),
+		JarFiles: []string{
+			writeTempFile(t, dir, "job.jar", "jar 1"),
+			writeTempFile(t, filepath.Join(dir, "lib"), "lib.jar", "jar 2"),
+		},
+		Artifacts: []Artifact{
+			{EntryName: "words", File: writeTempFile(t, dir, "words.txt", "hello")},
+		},
+	}
+
+	var request map[string]interface{}
+	files := map[string]string{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/jobs" {
+			t.Errorf("got %s %s, want POST /jobs", r.Method, r.URL.Path)
+		}
+		mr, err := r.MultipartReader()
+		if err != nil {
+			t.Error(err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		for {
+			part, err := mr.NextPart()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Error(err)
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			b, _ := io.ReadAll(part)
+			if part.FormName() == "request" {
+				if ct := part.Header.Get("Content-Type"); ct != "application/json" {
+					t.Errorf("request part has content type %q", ct)
+				}
+				if err := json.Unmarshal(b, &request); err != nil {
+					t.Errorf("decode request part: %v", err)
+				}
+				continue
+			}
+			files[part.FormName()+" "+part.FileName()] = string(b)
+		}
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte(`{"jobUrl":"/jobs/8ea123d2bdc3064f36b92889e43803ee"}`))
+	}))
+	defer srv.Close()
+	c, err := New(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := c.SubmitJob(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id := r.JobID(); id != "8ea123d2bdc3064f36b92889e43803ee" {
+		t.Errorf("JobID = %q", id)
+	}
+
+	wantRequest := map[string]interface{}{
+		"jobGraphFileName": "job.graph",
+		"jobJarFileNames":  []interface{}{"job.jar", "lib.jar"},
+		"jobArtifactFileNames": []interface{}{
+			map[string]interface{}{"entryName": "words", "fileName": "words.txt"},
+		},
+	}
+	if !reflect.DeepEqual(request, wantRequest) {
+		t.Errorf("request part = %v, want %v", request, wantRequest)
+	}
+	wantFiles := map[string]string{
+		"file_0 job.graph": "graph",
+		"file_1 job.jar":   "jar 1",
+		"file_2 lib.jar":   "jar 2",
+		"file_3 words.txt": "hello",
+	}
+	if !reflect.DeepEqual(files, wantFiles) {
+		t.Errorf("file parts = %v, want %v", files, wantFiles)
+	}
+}
+
+func TestSubmitJobRejectsDuplicateFileNames(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("job submitted despite duplicate file names")
+	}))
+	defer srv.Close()
+	c, err := New(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	_, err = c.SubmitJob(SubmitJobOpts{
+		JobGraphFile: writeTempFile(t, dir, "job.graph", "graph"),
+		JarFiles: []string{
+			writeTempFile(t, filepath.Join(dir, "a"), "job.jar", "jar 1"),
+			writeTempFile(t, filepath.Join(dir, "b"), "job.jar", "jar 2"),
+		},
+	})
+	if err == nil {
+		t.Fatal("SubmitJob succeeded, want a duplicate file name error")
+	}
+}
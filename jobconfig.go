@@ -0,0 +1,115 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+type JobConfigResp struct {
+	ID              string          `json:"jid"`
+	Name            string          `json:"name"`
+	ExecutionConfig ExecutionConfig `json:"execution-config"`
+}
+
+type ExecutionConfig struct {
+	ExecutionMode   string `json:"execution-mode"`
+	RestartStrategy string `json:"restart-strategy"`
+	JobParallelism  int    `json:"job-parallelism"`
+	ObjectReuseMode bool   `json:"object-reuse-mode"`
+
+	// UserConfig holds the global job parameters set by the
+	// program.
+	UserConfig map[string]string `json:"user-config"`
+}
+
+// JobConfig returns the execution configuration of a job.
+func (c *Client) JobConfig(jobID string) (JobConfigResp, error) {
+	return c.JobConfigCtx(context.Background(), jobID)
+}
+
+// JobConfigCtx is like JobConfig but uses ctx to cancel the
+// request.
+func (c *Client) JobConfigCtx(ctx context.Context, jobID string) (JobConfigResp, error) {
+	var r JobConfigResp
+	uri := fmt.Sprintf("/jobs/%s/config", jobID)
+	req, err := http.NewRequestWithContext(
+		ctx,
+		"GET",
+		c.url(uri),
+		nil,
+	)
+	if err != nil {
+		return r, err
+	}
+	b, err := c.client.Do(req)
+	if err != nil {
+		return r, err
+	}
+	err = json.Unmarshal(b, &r)
+	return r, err
+}
+
+type CheckpointConfigResp struct {
+	// Mode is "exactly_once" or "at_least_once".
+	Mode string `json:"mode"`
+
+	// Interval, Timeout and MinPause are in milliseconds.
+	Interval      int64 `json:"interval"`
+	Timeout       int64 `json:"timeout"`
+	MinPause      int64 `json:"min_pause"`
+	MaxConcurrent int   `json:"max_concurrent"`
+
+	Externalization Externalization `json:"externalization"`
+
+	StateBackend      string `json:"state_backend"`
+	CheckpointStorage string `json:"checkpoint_storage"`
+
+	UnalignedCheckpoints bool `json:"unaligned_checkpoints"`
+
+	// AlignedCheckpointTimeout is the time in milliseconds
+	// after which an aligned checkpoint switches to
+	// unaligned.
+	AlignedCheckpointTimeout int64 `json:"aligned_checkpoint_timeout"`
+
+	TolerableFailedCheckpoints  int  `json:"tolerable_failed_checkpoints"`
+	CheckpointsAfterTasksFinish bool `json:"checkpoints_after_tasks_finish"`
+
+	StateChangelogEnabled                    bool   `json:"state_changelog_enabled"`
+	ChangelogPeriodicMaterializationInterval int64  `json:"changelog_periodic_materialization_interval"`
+	ChangelogStorage                         string `json:"changelog_storage"`
+}
+
+type Externalization struct {
+	Enabled              bool `json:"enabled"`
+	DeleteOnCancellation bool `json:"delete_on_cancellation"`
+}
+
+// CheckpointConfig returns the checkpointing configuration
+// of a job.
+func (c *Client) CheckpointConfig(jobID string) (CheckpointConfigResp, error) {
+	return c.CheckpointConfigCtx(context.Background(), jobID)
+}
+
+// CheckpointConfigCtx is like CheckpointConfig but uses ctx
+// to cancel the request.
+func (c *Client) CheckpointConfigCtx(ctx context.Context, jobID string) (CheckpointConfigResp, error) {
+	var r CheckpointConfigResp
+	uri := fmt.Sprintf("/jobs/%s/checkpoints/config", jobID)
+	req, err := http.NewRequestWithContext(
+		ctx,
+		"GET",
+		c.url(uri),
+		nil,
+	)
+	if err != nil {
+		return r, err
+	}
+	b, err := c.client.Do(req)
+	if err != nil {
+		return r, err
+	}
+	err = json.Unmarshal(b, &r)
+	return r, err
+}
@@ -0,0 +1,137 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// JobManagerLogs lists the log files of the job manager.
+func (c *Client) JobManagerLogs() (LogsResp, error) {
+	return c.JobManagerLogsCtx(context.Background())
+}
+
+// JobManagerLogsCtx is like JobManagerLogs but uses ctx to
+// cancel the request.
+func (c *Client) JobManagerLogsCtx(ctx context.Context) (LogsResp, error) {
+	var r LogsResp
+	req, err := http.NewRequestWithContext(
+		ctx,
+		"GET",
+		c.url("/jobmanager/logs"),
+		nil,
+	)
+	if err != nil {
+		return r, err
+	}
+	b, err := c.client.Do(req)
+	if err != nil {
+		return r, err
+	}
+	err = json.Unmarshal(b, &r)
+	return r, err
+}
+
+// JobManagerLogFile returns a log file of the job manager,
+// as listed by JobManagerLogs. The caller must close it.
+func (c *Client) JobManagerLogFile(name string) (io.ReadCloser, error) {
+	return c.JobManagerLogFileCtx(context.Background(), name)
+}
+
+// JobManagerLogFileCtx is like JobManagerLogFile but uses
+// ctx to cancel the request and the read of the file.
+func (c *Client) JobManagerLogFileCtx(ctx context.Context, name string) (io.ReadCloser, error) {
+	return c.stream(ctx, fmt.Sprintf("/jobmanager/logs/%s", url.PathEscape(name)))
+}
+
+// JobManagerLog returns the main log file of the job
+// manager. The caller must close it.
+func (c *Client) JobManagerLog() (io.ReadCloser, error) {
+	return c.JobManagerLogCtx(context.Background())
+}
+
+// JobManagerLogCtx is like JobManagerLog but uses ctx to
+// cancel the request and the read of the log.
+func (c *Client) JobManagerLogCtx(ctx context.Context) (io.ReadCloser, error) {
+	return c.stream(ctx, "/jobmanager/log")
+}
+
+// JobManagerStdout returns the stdout file of the job
+// manager. The caller must close it.
+func (c *Client) JobManagerStdout() (io.ReadCloser, error) {
+	return c.JobManagerStdoutCtx(context.Background())
+}
+
+// JobManagerStdoutCtx is like JobManagerStdout but uses ctx
+// to cancel the request and the read of the file.
+func (c *Client) JobManagerStdoutCtx(ctx context.Context) (io.ReadCloser, error) {
+	return c.stream(ctx, "/jobmanager/stdout")
+}
+
+type EnvironmentResp struct {
+	JVM       JVMInfo  `json:"jvm"`
+	Classpath []string `json:"classpath"`
+}
+
+type JVMInfo struct {
+	Version string   `json:"version"`
+	Arch    string   `json:"arch"`
+	Options []string `json:"options"`
+}
+
+// JobManagerEnvironment returns the JVM and classpath of the
+// job manager.
+func (c *Client) JobManagerEnvironment() (EnvironmentResp, error) {
+	return c.JobManagerEnvironmentCtx(context.Background())
+}
+
+// JobManagerEnvironmentCtx is like JobManagerEnvironment but
+// uses ctx to cancel the request.
+func (c *Client) JobManagerEnvironmentCtx(ctx context.Context) (EnvironmentResp, error) {
+	var r EnvironmentResp
+	req, err := http.NewRequestWithContext(
+		ctx,
+		"GET",
+		c.url("/jobmanager/environment"),
+		nil,
+	)
+	if err != nil {
+		return r, err
+	}
+	b, err := c.client.Do(req)
+	if err != nil {
+		return r, err
+	}
+	err = json.Unmarshal(b, &r)
+	return r, err
+}
+
+// JobManagerThreadDump returns a thread dump of the job
+// manager.
+func (c *Client) JobManagerThreadDump() (ThreadDumpResp, error) {
+	return c.JobManagerThreadDumpCtx(context.Background())
+}
+
+// JobManagerThreadDumpCtx is like JobManagerThreadDump but
+// uses ctx to cancel the request.
+func (c *Client) JobManagerThreadDumpCtx(ctx context.Context) (ThreadDumpResp, error) {
+	var r ThreadDumpResp
+	req, err := http.NewRequestWithContext(
+		ctx,
+		"GET",
+		c.url("/jobmanager/thread-dump"),
+		nil,
+	)
+	if err != nil {
+		return r, err
+	}
+	b, err := c.client.Do(req)
+	if err != nil {
+		return r, err
+	}
+	err = json.Unmarshal(b, &r)
+	return r, err
+}
@@ -0,0 +1,61 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestJobManagerLogTimeout(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/jobmanager/log":
+			// Never answers.
+			select {
+			case <-r.Context().Done():
+			case <-release:
+			}
+		case "/jobmanager/stdout":
+			// Answers at once but writes the body slowly.
+			w.Write([]byte("line 1\n"))
+			w.(http.Flusher).Flush()
+			time.Sleep(300 * time.Millisecond)
+			w.Write([]byte("line 2\n"))
+		}
+	}))
+	defer srv.Close()
+	defer close(release)
+	c, err := New(srv.URL, WithTimeout(100*time.Millisecond), WithRetryPolicy(RetryPolicy{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The timeout applies until the response headers arrive.
+	start := time.Now()
+	_, err = c.JobManagerLog()
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("err = %v, want a deadline error", err)
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("JobManagerLog took %s, want the 100ms timeout to apply", d)
+	}
+
+	// It does not cut off reading the body.
+	r, err := c.JobManagerStdout()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	b, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "line 1\nline 2\n" {
+		t.Errorf("read %q, want both lines", b)
+	}
+}
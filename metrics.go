@@ -0,0 +1,392 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Aggregations computed by the aggregating metric scopes.
+const (
+	AggMin  = "min"
+	AggMax  = "max"
+	AggAvg  = "avg"
+	AggSum  = "sum"
+	AggSkew = "skew"
+)
+
+// MetricScope selects the component metrics are read from.
+// Create it with one of the scope functions, e.g. JobScope.
+type MetricScope struct {
+	path string
+
+	// subset is the query parameter which selects the
+	// aggregated components of an aggregating scope.
+	subset string
+}
+
+// Aggregated reports whether the scope aggregates the
+// metrics of several components, in which case the values
+// are in Min, Max, Avg, Sum and Skew instead of Value.
+func (s MetricScope) Aggregated() bool {
+	return s.subset != ""
+}
+
+func (s MetricScope) String() string {
+	return s.path
+}
+
+// JobManagerScope selects the metrics of the job manager.
+func JobManagerScope() MetricScope {
+	return MetricScope{path: "/jobmanager/metrics"}
+}
+
+// TaskManagerScope selects the metrics of a task manager.
+func TaskManagerScope(taskManagerID string) MetricScope {
+	return MetricScope{path: fmt.Sprintf("/taskmanagers/%s/metrics", taskManagerID)}
+}
+
+// TaskManagersScope aggregates the metrics of all task
+// managers, or of those in MetricsOpts.Subset.
+func TaskManagersScope() MetricScope {
+	return MetricScope{path: "/taskmanagers/metrics", subset: "taskmanagers"}
+}
+
+// JobScope selects the metrics of a job.
+func JobScope(jobID string) MetricScope {
+	return MetricScope{path: fmt.Sprintf("/jobs/%s/metrics", jobID)}
+}
+
+// JobsScope aggregates the metrics of all jobs, or of those
+// in MetricsOpts.Subset.
+func JobsScope() MetricScope {
+	return MetricScope{path: "/jobs/metrics", subset: "jobs"}
+}
+
+// VertexScope selects the metrics of a job vertex. Subtask
+// metrics are prefixed with the subtask index, e.g.
+// "0.numRecordsIn".
+func VertexScope(jobID, vertexID string) MetricScope {
+	return MetricScope{path: fmt.Sprintf("/jobs/%s/vertices/%s/metrics", jobID, vertexID)}
+}
+
+// SubtaskScope selects the metrics of a subtask.
+func SubtaskScope(jobID, vertexID string, subtask int) MetricScope {
+	return MetricScope{path: fmt.Sprintf("/jobs/%s/vertices/%s/subtasks/%d/metrics", jobID, vertexID, subtask)}
+}
+
+// SubtasksScope aggregates the metrics of all subtasks of a
+// vertex, or of those in MetricsOpts.Subset.
+func SubtasksScope(jobID, vertexID string) MetricScope {
+	return MetricScope{path: fmt.Sprintf("/jobs/%s/vertices/%s/subtasks/metrics", jobID, vertexID), subset: "subtasks"}
+}
+
+type MetricsOpts struct {
+	// Metrics (optional): IDs of the metrics to return. If
+	// empty, Flink only returns the IDs of the available
+	// metrics without values.
+	Metrics []string
+
+	// Agg (optional): aggregations to compute in an
+	// aggregating scope, e.g. AggMax. Flink computes all of
+	// them if empty.
+	Agg []string
+
+	// Subset (optional): the job IDs, task manager IDs or
+	// subtask indexes to aggregate in an aggregating scope.
+	Subset []string
+}
+
+// MetricValue is the value of a metric. Values which are
+// not reported or not numeric are NaN.
+type MetricValue struct {
+	ID string
+
+	// Value is the value of a metric of a single component
+	// and Raw the value as sent by Flink, which is useful
+	// for metrics which are not numeric.
+	Value float64
+	Raw   string
+
+	// Min, Max, Avg, Sum and Skew are the aggregations of an
+	// aggregating scope.
+	Min  float64
+	Max  float64
+	Avg  float64
+	Sum  float64
+	Skew float64
+}
+
+func (m *MetricValue) UnmarshalJSON(b []byte) error {
+	var aux struct {
+		ID    string      `json:"id"`
+		Value interface{} `json:"value"`
+		Min   interface{} `json:"min"`
+		Max   interface{} `json:"max"`
+		Avg   interface{} `json:"avg"`
+		Sum   interface{} `json:"sum"`
+		Skew  interface{} `json:"skew"`
+	}
+	if err := json.Unmarshal(b, &aux); err != nil {
+		return err
+	}
+	m.ID = aux.ID
+
+	switch v := aux.Value.(type) {
+	case string:
+		m.Raw = v
+	case float64:
+		m.Raw = strconv.FormatFloat(v, 'g', -1, 64)
+	}
+	var err error
+	if m.Value, err = jsonFloat(aux.Value); err != nil {
+		m.Value = math.NaN()
+	}
+
+	for _, a := range []struct {
+		dst *float64
+		v   interface{}
+	}{
+		{&m.Min, aux.Min},
+		{&m.Max, aux.Max},
+		{&m.Avg, aux.Avg},
+		{&m.Sum, aux.Sum},
+		{&m.Skew, aux.Skew},
+	} {
+		if *a.dst, err = jsonFloat(a.v); err != nil {
+			return fmt.Errorf("metric %s: %w", m.ID, err)
+		}
+	}
+	return nil
+}
+
+// Metrics returns metrics of the component selected by
+// scope.
+func (c *Client) Metrics(scope MetricScope, opts MetricsOpts) ([]MetricValue, error) {
+	return c.MetricsCtx(context.Background(), scope, opts)
+}
+
+// MetricsCtx is like Metrics but uses ctx to cancel the
+// request.
+func (c *Client) MetricsCtx(ctx context.Context, scope MetricScope, opts MetricsOpts) ([]MetricValue, error) {
+	var r []MetricValue
+	req, err := http.NewRequestWithContext(
+		ctx,
+		"GET",
+		c.url(scope.path),
+		nil,
+	)
+	if err != nil {
+		return r, err
+	}
+	q := req.URL.Query()
+	if len(opts.Metrics) > 0 {
+		q.Add("get", strings.Join(opts.Metrics, ","))
+	}
+	if scope.Aggregated() {
+		if len(opts.Agg) > 0 {
+			q.Add("agg", strings.Join(opts.Agg, ","))
+		}
+		if len(opts.Subset) > 0 {
+			q.Add(scope.subset, strings.Join(opts.Subset, ","))
+		}
+	}
+	req.URL.RawQuery = q.Encode()
+
+	b, err := c.client.Do(req)
+	if err != nil {
+		return r, err
+	}
+	err = json.Unmarshal(b, &r)
+	return r, err
+}
+
+// MetricIDs returns the IDs of all metrics available in
+// scope.
+func (c *Client) MetricIDs(scope MetricScope) ([]string, error) {
+	return c.MetricIDsCtx(context.Background(), scope)
+}
+
+// MetricIDsCtx is like MetricIDs but uses ctx to cancel the
+// request.
+func (c *Client) MetricIDsCtx(ctx context.Context, scope MetricScope) ([]string, error) {
+	metrics, err := c.MetricsCtx(ctx, scope, MetricsOpts{})
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, len(metrics))
+	for i, m := range metrics {
+		ids[i] = m.ID
+	}
+	return ids, nil
+}
+
+type FetchMetricsOpts struct {
+	// Match (optional): glob patterns as understood by
+	// path.Match, e.g. "*.numRecordsIn*". A metric is fetched
+	// if it matches any of them.
+	Match []string
+
+	// Regexp (optional): fetches the metrics it matches, in
+	// addition to those matched by Match. All metrics are
+	// fetched if neither Match nor Regexp is set.
+	Regexp *regexp.Regexp
+
+	// Agg and Subset (optional): as in MetricsOpts.
+	Agg    []string
+	Subset []string
+
+	// MaxURLLength (optional): longest request URL. Metrics
+	// are fetched in batches which fit into it.
+	// Defaults to 2048.
+	MaxURLLength int
+
+	// Concurrency (optional): number of batches fetched at
+	// the same time. Defaults to 4.
+	Concurrency int
+}
+
+// FetchMetrics discovers the metrics available in scope and
+// fetches the values of those selected by opts, keyed by
+// metric ID. The values are fetched in batches so that no
+// request URL exceeds the limit of the server.
+func (c *Client) FetchMetrics(ctx context.Context, scope MetricScope, opts FetchMetricsOpts) (map[string]MetricValue, error) {
+	if opts.MaxURLLength <= 0 {
+		opts.MaxURLLength = 2048
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = 4
+	}
+	for _, pattern := range opts.Match {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("metric pattern %q: %w", pattern, err)
+		}
+	}
+
+	ids, err := c.MetricIDsCtx(ctx, scope)
+	if err != nil {
+		return nil, err
+	}
+	var selected []string
+	for _, id := range ids {
+		if opts.matches(id) {
+			selected = append(selected, id)
+		}
+	}
+
+	base := MetricsOpts{Agg: opts.Agg, Subset: opts.Subset}
+	batches := c.metricBatches(scope, base, selected, opts.MaxURLLength)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		firstErr error
+	)
+	r := make(map[string]MetricValue, len(selected))
+	sem := make(chan struct{}, opts.Concurrency)
+	for _, batch := range batches {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		wg.Add(1)
+		go func(batch []string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			o := base
+			o.Metrics = batch
+			metrics, err := c.MetricsCtx(ctx, scope, o)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+					cancel()
+				}
+				return
+			}
+			for _, m := range metrics {
+				r[m.ID] = m
+			}
+		}(batch)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (o FetchMetricsOpts) matches(id string) bool {
+	if len(o.Match) == 0 && o.Regexp == nil {
+		return true
+	}
+	for _, pattern := range o.Match {
+		if ok, _ := path.Match(pattern, id); ok {
+			return true
+		}
+	}
+	return o.Regexp != nil && o.Regexp.MatchString(id)
+}
+
+// metricBatches splits ids into batches whose request URL is
+// at most maxLen long. An ID which does not fit on its own is
+// fetched alone.
+func (c *Client) metricBatches(scope MetricScope, opts MetricsOpts, ids []string, maxLen int) [][]string {
+	// The length of the URL without metrics, plus the
+	// "&get=" parameter.
+	baseLen := len(c.url(scope.path)) + len("?&get=")
+	if scope.Aggregated() {
+		q := url.Values{}
+		if len(opts.Agg) > 0 {
+			q.Add("agg", strings.Join(opts.Agg, ","))
+		}
+		if len(opts.Subset) > 0 {
+			q.Add(scope.subset, strings.Join(opts.Subset, ","))
+		}
+		baseLen += len(q.Encode())
+	}
+
+	var (
+		batches [][]string
+		batch   []string
+		n       = baseLen
+	)
+	for _, id := range ids {
+		l := len(url.QueryEscape(id))
+		if len(batch) > 0 {
+			// The escaped comma between IDs.
+			l += len("%2C")
+		}
+		if len(batch) > 0 && n+l > maxLen {
+			batches = append(batches, batch)
+			batch, n = nil, baseLen
+			l -= len("%2C")
+		}
+		batch = append(batch, id)
+		n += l
+	}
+	if len(batch) > 0 {
+		batches = append(batches, batch)
+	}
+	return batches
+}
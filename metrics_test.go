@@ -0,0 +1,127 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func operatorMetricIDs(n int) []string {
+	var ids []string
+	for i := 0; i < n; i++ {
+		// Spaces and brackets need escaping, which makes the
+		// URL longer than the ID.
+		ids = append(ids, fmt.Sprintf("%d.Source: Kafka [topic-%d] -> Map.numRecordsOutPerSecond", i%8, i))
+	}
+	return ids
+}
+
+func TestMetricBatches(t *testing.T) {
+	c, err := New("http://jobmanager:8081")
+	if err != nil {
+		t.Fatal(err)
+	}
+	scope := SubtasksScope("8ea123d2bdc3064f36b92889e43803ee", "cbc357ccb763df2852fee8c4fc7d55f2")
+	opts := MetricsOpts{Agg: []string{AggMax, AggSkew}, Subset: []string{"0", "1", "2"}}
+	ids := operatorMetricIDs(300)
+
+	for _, maxLen := range []int{300, 1000, 2048, 8192} {
+		batches := c.metricBatches(scope, opts, ids, maxLen)
+		var all []string
+		for _, batch := range batches {
+			o := opts
+			o.Metrics = batch
+			if l := len(metricsURL(t, c, scope, o)); l > maxLen && len(batch) > 1 {
+				t.Errorf("maxLen %d: batch of %d metrics has a URL of %d bytes", maxLen, len(batch), l)
+			}
+			all = append(all, batch...)
+		}
+		if !reflect.DeepEqual(all, ids) {
+			t.Errorf("maxLen %d: batches do not contain every ID once, in order", maxLen)
+		}
+	}
+}
+
+// metricsURL returns the URL MetricsCtx requests.
+func metricsURL(t *testing.T, c *Client, scope MetricScope, opts MetricsOpts) string {
+	t.Helper()
+	var got string
+	c.client.client.Transport = roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		got = req.URL.String()
+		return nil, fmt.Errorf("not sent")
+	})
+	c.MetricsCtx(context.Background(), scope, opts)
+	return got
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestFetchMetrics(t *testing.T) {
+	ids := operatorMetricIDs(200)
+	const maxLen = 1024
+
+	var (
+		mu       sync.Mutex
+		requests int
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if l := len("http://" + r.Host + r.URL.RequestURI()); l > maxLen {
+			t.Errorf("request URL of %d bytes", l)
+		}
+		get := r.URL.Query().Get("get")
+		var out []map[string]interface{}
+		if get == "" {
+			for _, id := range ids {
+				out = append(out, map[string]interface{}{"id": id})
+			}
+			out = append(out, map[string]interface{}{"id": "0.uptime"})
+		} else {
+			mu.Lock()
+			requests++
+			mu.Unlock()
+			for _, id := range strings.Split(get, ",") {
+				out = append(out, map[string]interface{}{"id": id, "max": 2.5})
+			}
+		}
+		json.NewEncoder(w).Encode(out)
+	}))
+	defer srv.Close()
+
+	c, err := New(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := c.FetchMetrics(context.Background(), SubtasksScope("j", "v"), FetchMetricsOpts{
+		Match:        []string{"*.numRecordsOutPerSecond"},
+		Agg:          []string{AggMax},
+		MaxURLLength: maxLen,
+		Concurrency:  3,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != len(ids) {
+		t.Errorf("got %d metrics, want %d", len(got), len(ids))
+	}
+	for _, id := range ids {
+		if got[id].Max != 2.5 {
+			t.Errorf("metric %q: max = %v, want 2.5", id, got[id].Max)
+		}
+	}
+	if _, ok := got["0.uptime"]; ok {
+		t.Error("fetched 0.uptime, which does not match")
+	}
+	if requests < 2 {
+		t.Errorf("fetched in %d batches, want several", requests)
+	}
+}
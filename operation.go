@@ -0,0 +1,316 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+)
+
+type OperationStatus string
+
+const (
+	OperationInProgress OperationStatus = "IN_PROGRESS"
+	OperationCompleted  OperationStatus = "COMPLETED"
+)
+
+// ErrOperationInProgress is returned by Operation.Result
+// while the operation has not completed yet.
+var ErrOperationInProgress = errors.New("flink: operation in progress")
+
+type OperationResult struct {
+	// Location is the path of the savepoint written by a
+	// savepoint or stop-with-savepoint operation.
+	Location string
+
+	// CheckpointID is the ID of the checkpoint written by a
+	// checkpoint operation.
+	CheckpointID int64
+}
+
+// Operation is a handle to an asynchronous operation, like
+// a savepoint, which Flink identifies by a trigger ID.
+type Operation struct {
+	// Kind names the operation, e.g. "savepoint".
+	Kind      string
+	TriggerID string
+
+	// WaitOpts controls how Wait polls the operation.
+	WaitOpts WaitOpts
+
+	c         *Client
+	statusURI string
+
+	mu     sync.Mutex
+	done   bool
+	result OperationResult
+	err    error
+}
+
+func (c *Client) newOperation(kind, triggerID, statusURI string) *Operation {
+	return &Operation{
+		Kind:      kind,
+		TriggerID: triggerID,
+		c:         c,
+		statusURI: statusURI,
+	}
+}
+
+type operationResp struct {
+	Status struct {
+		Id OperationStatus `json:"id"`
+	} `json:"status"`
+	Operation *struct {
+		Location     string        `json:"location"`
+		CheckpointID int64         `json:"checkpointId"`
+		FailureCause *FailureCause `json:"failure-cause"`
+	} `json:"operation"`
+}
+
+// Status queries the current status of the operation. Once
+// it is completed, the outcome is available from Result.
+func (o *Operation) Status(ctx context.Context) (OperationStatus, error) {
+	o.mu.Lock()
+	done := o.done
+	o.mu.Unlock()
+	if done {
+		return OperationCompleted, nil
+	}
+
+	var r operationResp
+	req, err := http.NewRequestWithContext(
+		ctx,
+		"GET",
+		o.c.url(o.statusURI),
+		nil,
+	)
+	if err != nil {
+		return "", err
+	}
+	b, err := o.c.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	if err := json.Unmarshal(b, &r); err != nil {
+		return "", err
+	}
+	if r.Status.Id != OperationCompleted {
+		return r.Status.Id, nil
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.done = true
+	if op := r.Operation; op != nil {
+		o.result = OperationResult{
+			Location:     op.Location,
+			CheckpointID: op.CheckpointID,
+		}
+		if op.FailureCause != nil && (op.FailureCause.Class != "" || op.FailureCause.StackTrace != "") {
+			o.err = newOperationError(o.Kind, o.TriggerID, *op.FailureCause)
+		}
+	}
+	return OperationCompleted, nil
+}
+
+// Result returns the outcome of a completed operation. If
+// the operation failed the error is an *OperationError; if
+// it has not completed yet, ErrOperationInProgress.
+func (o *Operation) Result() (OperationResult, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if !o.done {
+		return OperationResult{}, ErrOperationInProgress
+	}
+	return o.result, o.err
+}
+
+// Wait polls the operation until it completes and returns
+// its Result. If the wait is canceled or times out, the
+// error is an *OperationError wrapping the context error.
+func (o *Operation) Wait(ctx context.Context) (OperationResult, error) {
+	err := poll(ctx, o.WaitOpts, func(ctx context.Context) (bool, error) {
+		status, err := o.Status(ctx)
+		return status == OperationCompleted, err
+	})
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+		return OperationResult{}, &OperationError{
+			Operation: o.Kind,
+			TriggerID: o.TriggerID,
+			Err:       err,
+		}
+	}
+	if err != nil {
+		return OperationResult{}, err
+	}
+	return o.Result()
+}
+
+// TriggerSavepoint is like SavePoints but returns a handle
+// to the savepoint operation.
+func (c *Client) TriggerSavepoint(jobID string, saveDir string, cancelJob bool) (*Operation, error) {
+	return c.TriggerSavepointCtx(context.Background(), jobID, saveDir, cancelJob)
+}
+
+// TriggerSavepointCtx is like TriggerSavepoint but uses ctx
+// to cancel the request.
+func (c *Client) TriggerSavepointCtx(ctx context.Context, jobID string, saveDir string, cancelJob bool) (*Operation, error) {
+	r, err := c.SavePointsCtx(ctx, jobID, saveDir, cancelJob)
+	if err != nil {
+		return nil, err
+	}
+	return c.savepointOperation(jobID, r.RequestID), nil
+}
+
+func (c *Client) savepointOperation(jobID string, triggerID string) *Operation {
+	uri := fmt.Sprintf("/jobs/%s/savepoints/%s", jobID, triggerID)
+	return c.newOperation("savepoint", triggerID, uri)
+}
+
+// TriggerStopWithSavepoint is like StopJobWithSavepoint but
+// returns a handle to the stop operation.
+func (c *Client) TriggerStopWithSavepoint(jobID string, saveDir string, drain bool) (*Operation, error) {
+	return c.TriggerStopWithSavepointCtx(context.Background(), jobID, saveDir, drain)
+}
+
+// TriggerStopWithSavepointCtx is like
+// TriggerStopWithSavepoint but uses ctx to cancel the
+// request.
+func (c *Client) TriggerStopWithSavepointCtx(ctx context.Context, jobID string, saveDir string, drain bool) (*Operation, error) {
+	r, err := c.StopJobWithSavepointCtx(ctx, jobID, saveDir, drain)
+	if err != nil {
+		return nil, err
+	}
+	uri := fmt.Sprintf("/jobs/%s/savepoints/%s", jobID, r.RequestID)
+	return c.newOperation("stop-with-savepoint", r.RequestID, uri), nil
+}
+
+type CheckpointType string
+
+const (
+	CheckpointTypeConfigured  CheckpointType = "CONFIGURED"
+	CheckpointTypeFull        CheckpointType = "FULL"
+	CheckpointTypeIncremental CheckpointType = "INCREMENTAL"
+)
+
+type triggerResp struct {
+	RequestID string `json:"request-id"`
+}
+
+// TriggerCheckpoint triggers a checkpoint of a job. An
+// empty checkpointType uses the configured type.
+func (c *Client) TriggerCheckpoint(jobID string, checkpointType CheckpointType) (*Operation, error) {
+	return c.TriggerCheckpointCtx(context.Background(), jobID, checkpointType)
+}
+
+// TriggerCheckpointCtx is like TriggerCheckpoint but uses
+// ctx to cancel the request.
+func (c *Client) TriggerCheckpointCtx(ctx context.Context, jobID string, checkpointType CheckpointType) (*Operation, error) {
+	var r triggerResp
+	type CheckpointReq struct {
+		CheckpointType CheckpointType `json:"checkpointType,omitempty"`
+	}
+
+	d := CheckpointReq{
+		CheckpointType: checkpointType,
+	}
+	data := new(bytes.Buffer)
+	json.NewEncoder(data).Encode(d)
+	uri := fmt.Sprintf("/jobs/%s/checkpoints", jobID)
+	req, err := http.NewRequestWithContext(
+		ctx,
+		"POST",
+		c.url(uri),
+		data,
+	)
+	if err != nil {
+		return nil, err
+	}
+	b, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(b, &r); err != nil {
+		return nil, err
+	}
+	uri = fmt.Sprintf("/jobs/%s/checkpoints/%s", jobID, r.RequestID)
+	return c.newOperation("checkpoint", r.RequestID, uri), nil
+}
+
+// TriggerRescaling triggers the rescaling of a job to the
+// given parallelism.
+func (c *Client) TriggerRescaling(jobID string, parallelism int) (*Operation, error) {
+	return c.TriggerRescalingCtx(context.Background(), jobID, parallelism)
+}
+
+// TriggerRescalingCtx is like TriggerRescaling but uses ctx
+// to cancel the request.
+func (c *Client) TriggerRescalingCtx(ctx context.Context, jobID string, parallelism int) (*Operation, error) {
+	var r triggerResp
+	uri := fmt.Sprintf("/jobs/%s/rescaling", jobID)
+	req, err := http.NewRequestWithContext(
+		ctx,
+		"PATCH",
+		c.url(uri),
+		nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+	q := req.URL.Query()
+	q.Add("parallelism", strconv.Itoa(parallelism))
+	req.URL.RawQuery = q.Encode()
+	b, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(b, &r); err != nil {
+		return nil, err
+	}
+	uri = fmt.Sprintf("/jobs/%s/rescaling/%s", jobID, r.RequestID)
+	return c.newOperation("rescaling", r.RequestID, uri), nil
+}
+
+// TriggerSavepointDisposal triggers the disposal of the
+// savepoint at savepointPath.
+func (c *Client) TriggerSavepointDisposal(savepointPath string) (*Operation, error) {
+	return c.TriggerSavepointDisposalCtx(context.Background(), savepointPath)
+}
+
+// TriggerSavepointDisposalCtx is like
+// TriggerSavepointDisposal but uses ctx to cancel the
+// request.
+func (c *Client) TriggerSavepointDisposalCtx(ctx context.Context, savepointPath string) (*Operation, error) {
+	var r triggerResp
+	type DisposalReq struct {
+		SavepointPath string `json:"savepoint-path"`
+	}
+
+	d := DisposalReq{
+		SavepointPath: savepointPath,
+	}
+	data := new(bytes.Buffer)
+	json.NewEncoder(data).Encode(d)
+	req, err := http.NewRequestWithContext(
+		ctx,
+		"POST",
+		c.url("/savepoint-disposal"),
+		data,
+	)
+	if err != nil {
+		return nil, err
+	}
+	b, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(b, &r); err != nil {
+		return nil, err
+	}
+	uri := fmt.Sprintf("/savepoint-disposal/%s", r.RequestID)
+	return c.newOperation("savepoint-disposal", r.RequestID, uri), nil
+}
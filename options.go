@@ -0,0 +1,209 @@
+package api
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// Option configures a Client created by New.
+type Option func(*options) error
+
+type options struct {
+	client    *http.Client
+	transport http.RoundTripper
+	timeout   time.Duration
+	basePath  string
+	auth      Authenticator
+	retry     *RetryPolicy
+	endpoints []string
+	cooldown  time.Duration
+
+	tlsConfig    *tls.Config
+	rootCAs      *x509.CertPool
+	certificates []tls.Certificate
+	insecure     bool
+}
+
+// WithHTTPClient makes the client send its requests with
+// hc instead of a zero-value http.Client. hc is copied, so
+// other options never modify the caller's client. Its
+// Timeout is used like WithTimeout, unless that is set too.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(o *options) error {
+		if hc == nil {
+			return errors.New("api: nil http client")
+		}
+		o.client = hc
+		return nil
+	}
+}
+
+// WithTransport sets the http.RoundTripper used to send
+// requests. TLS options can only be combined with an
+// *http.Transport.
+func WithTransport(rt http.RoundTripper) Option {
+	return func(o *options) error {
+		if rt == nil {
+			return errors.New("api: nil transport")
+		}
+		o.transport = rt
+		return nil
+	}
+}
+
+// WithTimeout sets the default time limit for a request,
+// including retries and reading the response body. A
+// deadline set on the context of a Ctx method still applies
+// on top of it. For streamed responses like log files it
+// only limits the wait for the response headers, since
+// reading them may take arbitrarily long.
+func WithTimeout(d time.Duration) Option {
+	return func(o *options) error {
+		if d < 0 {
+			return fmt.Errorf("api: negative timeout %s", d)
+		}
+		o.timeout = d
+		return nil
+	}
+}
+
+// WithBasePath prefixes every request path with p, for job
+// managers served below a path by a reverse proxy, e.g.
+// "/flink".
+func WithBasePath(p string) Option {
+	return func(o *options) error {
+		p = strings.TrimRight(p, "/")
+		if p != "" && !strings.HasPrefix(p, "/") {
+			p = "/" + p
+		}
+		o.basePath = p
+		return nil
+	}
+}
+
+// WithTLSConfig sets the base TLS configuration. The other
+// TLS options are applied on top of a copy of cfg.
+func WithTLSConfig(cfg *tls.Config) Option {
+	return func(o *options) error {
+		if cfg == nil {
+			return errors.New("api: nil tls config")
+		}
+		o.tlsConfig = cfg
+		return nil
+	}
+}
+
+// WithCACert trusts the PEM encoded certificates in pem in
+// addition to the system roots.
+func WithCACert(pem []byte) Option {
+	return func(o *options) error {
+		if o.rootCAs == nil {
+			pool, err := x509.SystemCertPool()
+			if err != nil {
+				pool = x509.NewCertPool()
+			}
+			o.rootCAs = pool
+		}
+		if !o.rootCAs.AppendCertsFromPEM(pem) {
+			return errors.New("api: no certificates found in CA bundle")
+		}
+		return nil
+	}
+}
+
+// WithCAFile is like WithCACert but reads the bundle from
+// the file at path.
+func WithCAFile(path string) Option {
+	return func(o *options) error {
+		pem, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		return WithCACert(pem)(o)
+	}
+}
+
+// WithClientCert presents cert to the job manager, for
+// clusters which require mutual TLS.
+func WithClientCert(cert tls.Certificate) Option {
+	return func(o *options) error {
+		o.certificates = append(o.certificates, cert)
+		return nil
+	}
+}
+
+// WithClientCertFile is like WithClientCert but loads the
+// PEM encoded certificate and key from files.
+func WithClientCertFile(certFile, keyFile string) Option {
+	return func(o *options) error {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return err
+		}
+		return WithClientCert(cert)(o)
+	}
+}
+
+// WithInsecureSkipVerify disables verification of the
+// server certificate. Only use it for development clusters.
+func WithInsecureSkipVerify() Option {
+	return func(o *options) error {
+		o.insecure = true
+		return nil
+	}
+}
+
+func (o *options) usesTLS() bool {
+	return o.tlsConfig != nil || o.rootCAs != nil || len(o.certificates) > 0 || o.insecure
+}
+
+// httpClient builds the http.Client described by o.
+func (o *options) httpClient() (*http.Client, error) {
+	hc := &http.Client{}
+	if o.client != nil {
+		c := *o.client
+		hc = &c
+	}
+	if o.transport != nil {
+		hc.Transport = o.transport
+	}
+	// The timeout is applied per request by httpClient.Do,
+	// so that it does not cut off streamed responses.
+	hc.Timeout = 0
+	if !o.usesTLS() {
+		return hc, nil
+	}
+
+	rt := hc.Transport
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+	t, ok := rt.(*http.Transport)
+	if !ok {
+		return nil, fmt.Errorf("api: TLS options require an *http.Transport, got %T", rt)
+	}
+	t = t.Clone()
+
+	cfg := &tls.Config{}
+	if o.tlsConfig != nil {
+		cfg = o.tlsConfig.Clone()
+	} else if t.TLSClientConfig != nil {
+		cfg = t.TLSClientConfig.Clone()
+	}
+	if o.rootCAs != nil {
+		cfg.RootCAs = o.rootCAs
+	}
+	cfg.Certificates = append(cfg.Certificates, o.certificates...)
+	if o.insecure {
+		cfg.InsecureSkipVerify = true
+	}
+	t.TLSClientConfig = cfg
+	hc.Transport = t
+	return hc, nil
+}
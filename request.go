@@ -1,23 +1,60 @@
 package api
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
+	"mime/multipart"
 	"net/http"
+	"os"
+	"path/filepath"
+	"time"
 )
 
 type httpClient struct {
-	client http.Client
+	client    *http.Client
+	timeout   time.Duration
+	auth      Authenticator
+	retry     *RetryPolicy
+	endpoints *endpointPool
 }
 
-func newHttpClient() *httpClient {
-	return &httpClient{
-		client: http.Client{},
+func newHttpClient(o options, endpoints *endpointPool) (*httpClient, error) {
+	client, err := o.httpClient()
+	if err != nil {
+		return nil, err
+	}
+	timeout := o.timeout
+	if timeout == 0 && o.client != nil {
+		timeout = o.client.Timeout
 	}
+	retry := o.retry
+	if retry == nil {
+		p := DefaultRetryPolicy
+		retry = &p
+	}
+	return &httpClient{
+		client:    client,
+		timeout:   timeout,
+		auth:      o.auth,
+		retry:     retry,
+		endpoints: endpoints,
+	}, nil
 }
 
+// Do sends req and returns the response body. The request
+// context is honored while the body is read, so a canceled
+// context also aborts a slow response. The default timeout
+// covers the whole call, including retries.
 func (c *httpClient) Do(req *http.Request) ([]byte, error) {
-	resp, err := c.client.Do(req)
+	if c.timeout > 0 {
+		ctx, cancel := context.WithTimeout(req.Context(), c.timeout)
+		defer cancel()
+		req = req.WithContext(ctx)
+	}
+	resp, err := c.send(req)
 	if err != nil {
 		return nil, err
 	}
@@ -28,7 +65,216 @@ func (c *httpClient) Do(req *http.Request) ([]byte, error) {
 		return nil, err
 	}
 	if int(resp.StatusCode/100) != 2 {
-		return nil, fmt.Errorf("http status not 2xx: %d %s", resp.StatusCode, string(body))
+		return nil, newAPIError(req, resp, body)
 	}
 	return body, nil
 }
+
+// Stream sends req and returns the response body for the
+// caller to read and close. It is used for large responses
+// like log files which should not be buffered in memory, so
+// the default timeout only applies until the response
+// headers arrive; afterwards only the request context
+// limits it.
+func (c *httpClient) Stream(req *http.Request) (io.ReadCloser, error) {
+	ctx, cancel := context.WithCancel(req.Context())
+	var timer *time.Timer
+	if c.timeout > 0 {
+		timer = time.AfterFunc(c.timeout, cancel)
+	}
+	resp, err := c.send(req.WithContext(ctx))
+	if timer != nil && !timer.Stop() {
+		if err == nil {
+			resp.Body.Close()
+		}
+		cancel()
+		return nil, fmt.Errorf("flink: %s %s: no response within %s: %w", req.Method, req.URL, c.timeout, context.DeadlineExceeded)
+	}
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	if int(resp.StatusCode/100) != 2 {
+		defer cancel()
+		defer resp.Body.Close()
+		body, err := ioutil.ReadAll(io.LimitReader(resp.Body, 1<<20))
+		if err != nil {
+			return nil, err
+		}
+		return nil, newAPIError(req, resp, body)
+	}
+	return &cancelBody{ReadCloser: resp.Body, cancel: cancel}, nil
+}
+
+// cancelBody releases the context of a streamed response
+// when the body is closed.
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+// send sends req, retrying transient failures according to
+// the retry policy.
+func (c *httpClient) send(req *http.Request) (*http.Response, error) {
+	if !c.retry.appliesTo(req) {
+		return c.failover(req)
+	}
+	r := req
+	for n := 1; ; n++ {
+		resp, err := c.failover(r)
+		if n >= c.retry.MaxAttempts || !c.retry.shouldRetry(resp, err) || !canRewind(req) {
+			return resp, err
+		}
+		wait := c.retry.backoff(n, resp)
+		if deadline, ok := req.Context().Deadline(); ok && time.Until(deadline) < wait {
+			// The retry could not be sent in time, so report
+			// this attempt rather than the expired context.
+			return resp, err
+		}
+		if resp != nil {
+			drain(resp)
+		}
+		if err := sleep(req.Context(), wait); err != nil {
+			return nil, err
+		}
+		if r, err = rewind(req); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// attempt sends req with the configured credentials. When
+// the server answers 401 and the credentials can be
+// refreshed, they are refreshed and the request is sent
+// once more.
+func (c *httpClient) attempt(req *http.Request) (*http.Response, error) {
+	sent, resp, err := c.sendAuth(req)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+	refresher, ok := c.auth.(Refresher)
+	if !ok {
+		return resp, nil
+	}
+	if !canRewind(req) {
+		return resp, nil
+	}
+	drain(resp)
+	if err := refresher.Refresh(req.Context(), sent); err != nil {
+		return nil, fmt.Errorf("refresh credentials: %w", err)
+	}
+	// The body is only rewound now, since a multipart body
+	// starts writing its form as soon as it is created.
+	retry, err := rewind(req)
+	if err != nil {
+		return nil, err
+	}
+	_, resp, err = c.sendAuth(retry)
+	return resp, err
+}
+
+// sendAuth sends req with the configured credentials and
+// returns the request as sent, including the credentials.
+func (c *httpClient) sendAuth(req *http.Request) (*http.Request, *http.Response, error) {
+	if info := callInfoFrom(req.Context()); info != nil {
+		info.Endpoint = req.URL.Host
+		info.Attempts++
+	}
+	if c.auth != nil {
+		req = req.Clone(req.Context())
+		if err := c.auth.Authenticate(req); err != nil {
+			if req.Body != nil {
+				req.Body.Close()
+			}
+			return req, nil, err
+		}
+	}
+	resp, err := c.client.Do(req)
+	return req, resp, err
+}
+
+var errNotRewindable = errors.New("api: request body cannot be resent")
+
+func canRewind(req *http.Request) bool {
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+// rewind returns a copy of req with a fresh body, which can
+// be sent after req itself has been sent.
+func rewind(req *http.Request) (*http.Request, error) {
+	r := req.Clone(req.Context())
+	if !canRewind(req) {
+		return nil, errNotRewindable
+	}
+	if req.Body == nil || req.Body == http.NoBody {
+		return r, nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	r.Body = body
+	return r, nil
+}
+
+// drain discards the rest of resp so the connection can be
+// reused.
+func drain(resp *http.Response) {
+	io.Copy(ioutil.Discard, io.LimitReader(resp.Body, 64<<10))
+	resp.Body.Close()
+}
+
+// newMultipartRequest returns a POST request whose body
+// streams the form produced by write. The form is generated
+// while the request is sent, so large files are never
+// buffered in memory and closing the body (as the transport
+// does when the request is canceled) stops write. write is
+// called again whenever the request has to be resent.
+func newMultipartRequest(ctx context.Context, url string, write func(*multipart.Writer) error) (*http.Request, error) {
+	mw := multipart.NewWriter(ioutil.Discard)
+	boundary, contentType := mw.Boundary(), mw.FormDataContentType()
+	getBody := func() (io.ReadCloser, error) {
+		pr, pw := io.Pipe()
+		go func() {
+			mw := multipart.NewWriter(pw)
+			mw.SetBoundary(boundary)
+			err := write(mw)
+			if err == nil {
+				err = mw.Close()
+			}
+			pw.CloseWithError(err)
+		}()
+		return pr, nil
+	}
+
+	body, _ := getBody()
+	req, err := http.NewRequestWithContext(ctx, "POST", url, body)
+	if err != nil {
+		body.Close()
+		return nil, err
+	}
+	req.GetBody = getBody
+	req.Header.Set("Content-Type", contentType)
+	return req, nil
+}
+
+// writeFormFile adds the file at path to w as field.
+func writeFormFile(w *multipart.Writer, field, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	part, err := w.CreateFormFile(field, filepath.Base(path))
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(part, file)
+	return err
+}
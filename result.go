@@ -0,0 +1,113 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+type AccumulatorsResp struct {
+	JobAccumulators      []Accumulator `json:"job-accumulators"`
+	UserTaskAccumulators []Accumulator `json:"user-task-accumulators"`
+
+	// SerializedUserTaskAccumulators maps accumulator names
+	// to their base64 encoded, Java serialized values. It is
+	// only set when requested.
+	SerializedUserTaskAccumulators map[string]string `json:"serialized-user-task-accumulators"`
+}
+
+type Accumulator struct {
+	Name  string `json:"name"`
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+// Accumulators returns the accumulators of a job. If
+// includeSerialized is set, the serialized values of user
+// accumulators are included as well.
+func (c *Client) Accumulators(jobID string, includeSerialized bool) (AccumulatorsResp, error) {
+	return c.AccumulatorsCtx(context.Background(), jobID, includeSerialized)
+}
+
+// AccumulatorsCtx is like Accumulators but uses ctx to
+// cancel the request.
+func (c *Client) AccumulatorsCtx(ctx context.Context, jobID string, includeSerialized bool) (AccumulatorsResp, error) {
+	var r AccumulatorsResp
+	uri := fmt.Sprintf("/jobs/%s/accumulators", jobID)
+	req, err := http.NewRequestWithContext(
+		ctx,
+		"GET",
+		c.url(uri),
+		nil,
+	)
+	if err != nil {
+		return r, err
+	}
+	if includeSerialized {
+		q := req.URL.Query()
+		q.Add("includeSerializedValue", strconv.FormatBool(includeSerialized))
+		req.URL.RawQuery = q.Encode()
+	}
+	b, err := c.client.Do(req)
+	if err != nil {
+		return r, err
+	}
+	err = json.Unmarshal(b, &r)
+	return r, err
+}
+
+type ExecutionResultResp struct {
+	Status struct {
+		Id OperationStatus `json:"id"`
+	} `json:"status"`
+
+	// JobExecutionResult is only set once the job reached a
+	// terminal state.
+	JobExecutionResult *JobExecutionResult `json:"job-execution-result"`
+}
+
+type JobExecutionResult struct {
+	ID                string `json:"id"`
+	ApplicationStatus string `json:"application-status"`
+
+	// NetRuntime is the runtime of the job in milliseconds.
+	NetRuntime int64 `json:"net-runtime"`
+
+	// AccumulatorResults maps accumulator names to their
+	// base64 encoded, Java serialized values.
+	AccumulatorResults map[string]string `json:"accumulator-results"`
+
+	// FailureCause is set when the job failed.
+	FailureCause *FailureCause `json:"failure-cause"`
+}
+
+// ExecutionResult returns the result of a job. While the
+// job is running the status is OperationInProgress and
+// JobExecutionResult is nil.
+func (c *Client) ExecutionResult(jobID string) (ExecutionResultResp, error) {
+	return c.ExecutionResultCtx(context.Background(), jobID)
+}
+
+// ExecutionResultCtx is like ExecutionResult but uses ctx
+// to cancel the request.
+func (c *Client) ExecutionResultCtx(ctx context.Context, jobID string) (ExecutionResultResp, error) {
+	var r ExecutionResultResp
+	uri := fmt.Sprintf("/jobs/%s/execution-result", jobID)
+	req, err := http.NewRequestWithContext(
+		ctx,
+		"GET",
+		c.url(uri),
+		nil,
+	)
+	if err != nil {
+		return r, err
+	}
+	b, err := c.client.Do(req)
+	if err != nil {
+		return r, err
+	}
+	err = json.Unmarshal(b, &r)
+	return r, err
+}
@@ -0,0 +1,182 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy describes how failed requests are retried.
+// Unless WithRetryPolicy is given, DefaultRetryPolicy is
+// used.
+//
+// GET and HEAD requests are retried automatically. Other
+// requests, e.g. RunJar or SavePoints, are only retried when
+// their context was derived with AllowRetry.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts of a
+	// request, including the first one. Values below 2
+	// disable retries.
+	MaxAttempts int
+
+	// InitialBackoff is the wait before the first retry. It
+	// doubles with every attempt up to MaxBackoff.
+	InitialBackoff time.Duration
+
+	// MaxBackoff caps the wait between two attempts. It does
+	// not apply to a Retry-After header sent by the server.
+	MaxBackoff time.Duration
+
+	// MaxRetryAfter caps the wait requested by a Retry-After
+	// header. Zero honors the header as long as the request
+	// context allows.
+	MaxRetryAfter time.Duration
+
+	// Jitter is the fraction, between 0 and 1, by which a
+	// wait is randomly shortened, so that clients don't retry
+	// in lockstep.
+	Jitter float64
+
+	// RetryableStatus lists the HTTP status codes which are
+	// retried. Connection errors are always retried.
+	RetryableStatus []int
+}
+
+// DefaultRetryPolicy retries transient errors like those
+// seen during a job manager failover.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:    4,
+	InitialBackoff: 200 * time.Millisecond,
+	MaxBackoff:     5 * time.Second,
+	MaxRetryAfter:  30 * time.Second,
+	Jitter:         0.5,
+	RetryableStatus: []int{
+		http.StatusTooManyRequests,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout,
+	},
+}
+
+// WithRetryPolicy makes the client retry failed requests
+// according to p. Pass the zero RetryPolicy to disable
+// retries.
+func WithRetryPolicy(p RetryPolicy) Option {
+	return func(o *options) error {
+		o.retry = &p
+		return nil
+	}
+}
+
+type allowRetryKey struct{}
+
+// AllowRetry returns a context which allows the retry
+// policy to be applied to non-idempotent requests made with
+// it, e.g. RunJarCtx or SavePointsCtx.
+func AllowRetry(ctx context.Context) context.Context {
+	return context.WithValue(ctx, allowRetryKey{}, true)
+}
+
+func (p *RetryPolicy) appliesTo(req *http.Request) bool {
+	if p == nil || p.MaxAttempts < 2 {
+		return false
+	}
+	switch req.Method {
+	case http.MethodGet, http.MethodHead:
+		return true
+	}
+	allowed, _ := req.Context().Value(allowRetryKey{}).(bool)
+	return allowed
+}
+
+// shouldRetry reports whether a request which ended with
+// resp or err is worth another attempt.
+func (p *RetryPolicy) shouldRetry(resp *http.Response, err error) bool {
+	if err != nil {
+		return isConnError(err)
+	}
+	for _, code := range p.RetryableStatus {
+		if resp.StatusCode == code {
+			return true
+		}
+	}
+	return false
+}
+
+// backoff returns the wait before the given retry, starting
+// at 1. A Retry-After header in resp takes precedence and is
+// only capped by MaxRetryAfter.
+func (p *RetryPolicy) backoff(retry int, resp *http.Response) time.Duration {
+	if d, ok := retryAfter(resp); ok {
+		if p.MaxRetryAfter > 0 && d > p.MaxRetryAfter {
+			return p.MaxRetryAfter
+		}
+		return d
+	}
+	d := p.InitialBackoff
+	for i := 1; i < retry && (p.MaxBackoff <= 0 || d < p.MaxBackoff); i++ {
+		d *= 2
+	}
+	if p.MaxBackoff > 0 && d > p.MaxBackoff {
+		d = p.MaxBackoff
+	}
+	if p.Jitter > 0 {
+		d -= time.Duration(rand.Float64() * p.Jitter * float64(d))
+	}
+	return d
+}
+
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	if resp == nil {
+		return 0, false
+	}
+	v := resp.Header.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		d := time.Until(t)
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+	return 0, false
+}
+
+// isConnError reports whether err was caused by the
+// connection to the server rather than by the request.
+func isConnError(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+	var opErr *net.OpError
+	if errors.As(err, &opErr) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// sleep waits for d or until ctx is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,168 @@
+package api
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fastRetry retries like DefaultRetryPolicy without waiting.
+var fastRetry = RetryPolicy{
+	MaxAttempts:     4,
+	InitialBackoff:  time.Millisecond,
+	MaxBackoff:      time.Millisecond,
+	RetryableStatus: DefaultRetryPolicy.RetryableStatus,
+}
+
+// flakyServer answers the first failures requests with 503
+// and the later ones with body. It records the bodies of all
+// requests.
+type flakyServer struct {
+	failures int
+	body     string
+
+	mu       sync.Mutex
+	requests []string
+}
+
+func (s *flakyServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	b, _ := io.ReadAll(r.Body)
+	s.mu.Lock()
+	s.requests = append(s.requests, string(b))
+	n := len(s.requests)
+	s.mu.Unlock()
+	if n <= s.failures {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	w.Write([]byte(s.body))
+}
+
+func TestRetry(t *testing.T) {
+	tests := []struct {
+		name     string
+		failures int
+		call     func(c *Client) error
+		attempts int
+		wantErr  bool
+	}{
+		{
+			name:     "GET is retried",
+			failures: 2,
+			call: func(c *Client) error {
+				_, err := c.Jobs()
+				return err
+			},
+			attempts: 3,
+		},
+		{
+			name:     "GET gives up after MaxAttempts",
+			failures: 10,
+			call: func(c *Client) error {
+				_, err := c.Jobs()
+				return err
+			},
+			attempts: 4,
+			wantErr:  true,
+		},
+		{
+			name:     "POST is not retried",
+			failures: 1,
+			call: func(c *Client) error {
+				_, err := c.RunJar(RunOpts{JarID: "a.jar"})
+				return err
+			},
+			attempts: 1,
+			wantErr:  true,
+		},
+		{
+			name:     "POST is retried with AllowRetry",
+			failures: 1,
+			call: func(c *Client) error {
+				_, err := c.RunJarCtx(AllowRetry(context.Background()), RunOpts{JarID: "a.jar"})
+				return err
+			},
+			attempts: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &flakyServer{failures: tt.failures, body: `{}`}
+			srv := httptest.NewServer(s)
+			defer srv.Close()
+			c, err := New(srv.URL, WithRetryPolicy(fastRetry))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = tt.call(c)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, want error %v", err, tt.wantErr)
+			}
+			if tt.wantErr && !IsStatus(err, http.StatusServiceUnavailable) {
+				t.Errorf("err = %v, want a 503 APIError", err)
+			}
+			if len(s.requests) != tt.attempts {
+				t.Fatalf("got %d attempts, want %d", len(s.requests), tt.attempts)
+			}
+			for i, body := range s.requests {
+				if body != s.requests[0] {
+					t.Errorf("attempt %d sent body %q, want %q", i+1, body, s.requests[0])
+				}
+			}
+		})
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	p := RetryPolicy{InitialBackoff: time.Millisecond, MaxBackoff: 5 * time.Second}
+	resp := &http.Response{Header: http.Header{"Retry-After": []string{"2"}}}
+	if d := p.backoff(1, resp); d != 2*time.Second {
+		t.Errorf("backoff = %s, want 2s", d)
+	}
+	resp.Header.Set("Retry-After", "60")
+	if d := p.backoff(1, resp); d != time.Minute {
+		t.Errorf("backoff = %s, want 1m0s despite MaxBackoff", d)
+	}
+	p.MaxRetryAfter = 10 * time.Second
+	if d := p.backoff(1, resp); d != p.MaxRetryAfter {
+		t.Errorf("backoff = %s, want MaxRetryAfter %s", d, p.MaxRetryAfter)
+	}
+
+	resp.Header.Set("Retry-After", "3600")
+	if d := DefaultRetryPolicy.backoff(1, resp); d != 30*time.Second {
+		t.Errorf("default backoff = %s, want 30s", d)
+	}
+}
+
+func TestRetryAfterBeyondDeadline(t *testing.T) {
+	var attempts int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+	c, err := New(srv.URL, WithRetryPolicy(fastRetry))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	start := time.Now()
+	_, err = c.JobsCtx(ctx)
+	if !IsStatus(err, http.StatusServiceUnavailable) {
+		t.Errorf("err = %v, want the 503 APIError", err)
+	}
+	if attempts != 1 {
+		t.Errorf("got %d attempts, want 1", attempts)
+	}
+	if d := time.Since(start); d > 500*time.Millisecond {
+		t.Errorf("call took %s, want it to return without waiting", d)
+	}
+}
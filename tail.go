@@ -0,0 +1,242 @@
+package api
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"regexp"
+	"time"
+)
+
+// LogSource selects the log file TailLog follows. Create it
+// with JobManagerLogSource or TaskManagerLogSource.
+type LogSource struct {
+	taskManagerID string
+	jobManager    bool
+
+	// name is the name of the log file, or empty for the
+	// main log.
+	name string
+}
+
+// JobManagerLogSource selects a log file of the job manager,
+// as listed by JobManagerLogs. An empty name selects the
+// main log.
+func JobManagerLogSource(name string) LogSource {
+	return LogSource{jobManager: true, name: name}
+}
+
+// TaskManagerLogSource selects a log file of a task manager,
+// as listed by TaskManagerLogs. An empty name selects the
+// main log.
+func TaskManagerLogSource(taskManagerID string, name string) LogSource {
+	return LogSource{taskManagerID: taskManagerID, name: name}
+}
+
+func (c *Client) openLog(ctx context.Context, s LogSource) (io.ReadCloser, error) {
+	switch {
+	case s.jobManager && s.name == "":
+		return c.JobManagerLogCtx(ctx)
+	case s.jobManager:
+		return c.JobManagerLogFileCtx(ctx, s.name)
+	case s.name == "":
+		return c.TaskManagerLogCtx(ctx, s.taskManagerID)
+	default:
+		return c.TaskManagerLogFileCtx(ctx, s.taskManagerID, s.name)
+	}
+}
+
+type TailOpts struct {
+	// PollInterval (optional): wait between two fetches of
+	// the log. Defaults to 2s.
+	PollInterval time.Duration
+
+	// Lines (optional): number of existing lines to emit
+	// before following the log, like tail -n. Ignored if
+	// FromStart is set.
+	Lines int
+
+	// FromStart (optional): emits the whole log before
+	// following it.
+	FromStart bool
+
+	// Filter (optional): only lines it matches are emitted.
+	Filter *regexp.Regexp
+}
+
+// tailCheckLen is the number of bytes before the offset
+// which are compared to detect a rotated log.
+const tailCheckLen = 1024
+
+var errLogRotated = errors.New("log rotated")
+
+// TailLog follows a log file and returns its new lines as
+// they are written, like tail -f. Flink has no way to fetch
+// part of a log, so the whole log is fetched every
+// PollInterval and the lines already emitted are skipped.
+// When the log was truncated or rotated, it is followed
+// again from its start.
+//
+// The returned reader only yields complete lines. Reading
+// fails with the error which stopped the tail, e.g. ctx's
+// error. Close the reader to stop following the log.
+func (c *Client) TailLog(ctx context.Context, source LogSource, opts TailOpts) (io.ReadCloser, error) {
+	if !source.jobManager && source.taskManagerID == "" {
+		return nil, errors.New("api: log source not set")
+	}
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = 2 * time.Second
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	pr, pw := io.Pipe()
+	context.AfterFunc(ctx, func() {
+		pw.CloseWithError(ctx.Err())
+	})
+	t := &logTail{
+		c:      c,
+		source: source,
+		opts:   opts,
+		w:      pw,
+	}
+	go func() {
+		// The pipe keeps the first error, so canceling
+		// afterwards only releases the context.
+		pw.CloseWithError(t.run(ctx))
+		cancel()
+	}()
+	return &tailReader{PipeReader: pr, cancel: cancel}, nil
+}
+
+type tailReader struct {
+	*io.PipeReader
+	cancel context.CancelFunc
+}
+
+func (r *tailReader) Close() error {
+	r.cancel()
+	return r.PipeReader.Close()
+}
+
+type logTail struct {
+	c      *Client
+	source LogSource
+	opts   TailOpts
+	w      io.Writer
+
+	// offset is the number of bytes of the log already
+	// processed, check holds the last bytes before offset.
+	offset int64
+	check  []byte
+}
+
+func (t *logTail) run(ctx context.Context) error {
+	first := true
+	for {
+		err := t.fetch(ctx, first)
+		if errors.Is(err, errLogRotated) {
+			t.offset, t.check = 0, nil
+			first = false
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		first = false
+		if err := sleep(ctx, t.opts.PollInterval); err != nil {
+			return err
+		}
+	}
+}
+
+// fetch fetches the log and emits the complete lines after
+// offset. On the first fetch, only the lines selected by
+// Lines or FromStart are emitted.
+func (t *logTail) fetch(ctx context.Context, first bool) error {
+	body, err := t.c.openLog(ctx, t.source)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	r := bufio.NewReader(body)
+	if err := t.skip(r); err != nil {
+		return err
+	}
+
+	emit := !first || t.opts.FromStart
+	var last [][]byte
+	for {
+		line, err := r.ReadBytes('\n')
+		if err == io.EOF {
+			// An incomplete last line is emitted once it is
+			// complete.
+			break
+		}
+		if err != nil {
+			return err
+		}
+		t.advance(line)
+		if !t.matches(line) {
+			continue
+		}
+		if emit {
+			if _, err := t.w.Write(line); err != nil {
+				return err
+			}
+		} else if t.opts.Lines > 0 {
+			if len(last) == t.opts.Lines {
+				last = last[1:]
+			}
+			last = append(last, line)
+		}
+	}
+	for _, line := range last {
+		if _, err := t.w.Write(line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// skip discards the bytes of r before offset. It returns
+// errLogRotated if the log is shorter than offset or the
+// bytes before offset changed.
+func (t *logTail) skip(r io.Reader) error {
+	n := t.offset - int64(len(t.check))
+	if _, err := io.CopyN(io.Discard, r, n); err != nil {
+		if err == io.EOF {
+			return errLogRotated
+		}
+		return err
+	}
+	b := make([]byte, len(t.check))
+	if _, err := io.ReadFull(r, b); err != nil {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return errLogRotated
+		}
+		return err
+	}
+	if !bytes.Equal(b, t.check) {
+		return errLogRotated
+	}
+	return nil
+}
+
+func (t *logTail) advance(line []byte) {
+	t.offset += int64(len(line))
+	if len(line) >= tailCheckLen {
+		t.check = append(t.check[:0], line[len(line)-tailCheckLen:]...)
+		return
+	}
+	t.check = append(t.check, line...)
+	if over := len(t.check) - tailCheckLen; over > 0 {
+		t.check = t.check[over:]
+	}
+}
+
+func (t *logTail) matches(line []byte) bool {
+	return t.opts.Filter == nil || t.opts.Filter.Match(bytes.TrimRight(line, "\r\n"))
+}
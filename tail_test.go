@@ -0,0 +1,172 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// logServer serves a log file whose content can be changed.
+type logServer struct {
+	mu  sync.Mutex
+	log string
+}
+
+func (s *logServer) set(log string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.log = log
+}
+
+func (s *logServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	io.WriteString(w, s.log)
+}
+
+// lineReader reads the lines of a tail in the background.
+func lineReader(r io.Reader) <-chan string {
+	lines := make(chan string)
+	go func() {
+		defer close(lines)
+		sc := bufio.NewScanner(r)
+		for sc.Scan() {
+			lines <- sc.Text()
+		}
+	}()
+	return lines
+}
+
+func expectLines(t *testing.T, lines <-chan string, want ...string) {
+	t.Helper()
+	for _, w := range want {
+		select {
+		case got, ok := <-lines:
+			if !ok {
+				t.Fatalf("tail ended, want line %q", w)
+			}
+			if got != w {
+				t.Fatalf("got line %q, want %q", got, w)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("timed out waiting for line %q", w)
+		}
+	}
+}
+
+func expectNoLine(t *testing.T, lines <-chan string) {
+	t.Helper()
+	select {
+	case got := <-lines:
+		t.Fatalf("got unexpected line %q", got)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestTailLog(t *testing.T) {
+	s := &logServer{log: "old 1\nold 2\nold 3\n"}
+	srv := httptest.NewServer(s)
+	defer srv.Close()
+	c, err := New(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	tail, err := c.TailLog(ctx, TaskManagerLogSource("tm-1", ""), TailOpts{
+		PollInterval: 10 * time.Millisecond,
+		Lines:        2,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tail.Close()
+	lines := lineReader(tail)
+
+	expectLines(t, lines, "old 2", "old 3")
+
+	// Appended lines are emitted once they are complete.
+	s.set("old 1\nold 2\nold 3\nnew 1\nnew")
+	expectLines(t, lines, "new 1")
+	expectNoLine(t, lines)
+	s.set("old 1\nold 2\nold 3\nnew 1\nnew 2\n")
+	expectLines(t, lines, "new 2")
+
+	// A truncated log is followed from its start.
+	s.set("trunc 1\n")
+	expectLines(t, lines, "trunc 1")
+
+	// So is a rotated log, even if it is already longer than
+	// the old one.
+	s.set("rotated 1\nrotated 2\n" + strings.Repeat("rotated 3 is a long line\n", 4))
+	expectLines(t, lines, "rotated 1", "rotated 2")
+	for i := 0; i < 4; i++ {
+		expectLines(t, lines, "rotated 3 is a long line")
+	}
+	expectNoLine(t, lines)
+}
+
+func TestTailLogFilter(t *testing.T) {
+	s := &logServer{log: "INFO a\nWARN b\n"}
+	srv := httptest.NewServer(s)
+	defer srv.Close()
+	c, err := New(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	tail, err := c.TailLog(ctx, JobManagerLogSource(""), TailOpts{
+		PollInterval: 10 * time.Millisecond,
+		FromStart:    true,
+		Filter:       regexp.MustCompile(`^(WARN|ERROR)`),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := lineReader(tail)
+
+	expectLines(t, lines, "WARN b")
+	s.set("INFO a\nWARN b\nINFO c\nERROR d\n")
+	expectLines(t, lines, "ERROR d")
+
+	// Closing the tail ends it.
+	tail.Close()
+	select {
+	case _, ok := <-lines:
+		if ok {
+			t.Fatal("got a line after Close")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("tail did not end after Close")
+	}
+}
+
+func TestTailLogNotFound(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	defer srv.Close()
+	c, err := New(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tail, err := c.TailLog(context.Background(), TaskManagerLogSource("tm-1", "missing.log"), TailOpts{
+		PollInterval: 10 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tail.Close()
+	if _, err := io.ReadAll(tail); !IsNotFound(err) {
+		t.Errorf("err = %v, want a 404 APIError", err)
+	}
+}
@@ -0,0 +1,322 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+type TaskManagersResp struct {
+	TaskManagers []TaskManagerInfo `json:"taskmanagers"`
+}
+
+type TaskManagerInfo struct {
+	ID       string `json:"id"`
+	Path     string `json:"path"`
+	DataPort int    `json:"dataPort"`
+	JmxPort  int    `json:"jmxPort"`
+
+	// TimeSinceLastHeartbeat is the timestamp in
+	// milliseconds of the last heartbeat, despite its name.
+	TimeSinceLastHeartbeat int64 `json:"timeSinceLastHeartbeat"`
+
+	SlotsNumber int `json:"slotsNumber"`
+	FreeSlots   int `json:"freeSlots"`
+
+	TotalResource TaskManagerResource `json:"totalResource"`
+	FreeResource  TaskManagerResource `json:"freeResource"`
+
+	Hardware            Hardware                `json:"hardware"`
+	MemoryConfiguration TaskManagerMemoryConfig `json:"memoryConfiguration"`
+
+	// Blocked is set when the task manager is blocked for
+	// new slots, e.g. by speculative execution.
+	Blocked bool `json:"blocked"`
+}
+
+// LastHeartbeat returns the time of the last heartbeat of
+// the task manager.
+func (t TaskManagerInfo) LastHeartbeat() time.Time {
+	return msTime(t.TimeSinceLastHeartbeat)
+}
+
+// TaskManagerResource describes the resources of the slots
+// of a task manager. Unlike the other memory sizes of the
+// task manager, which are in bytes, Flink reports these in
+// MiB.
+type TaskManagerResource struct {
+	CPUCores          float64            `json:"cpuCores"`
+	TaskHeapMemory    int64              `json:"taskHeapMemory"`
+	TaskOffHeapMemory int64              `json:"taskOffHeapMemory"`
+	ManagedMemory     int64              `json:"managedMemory"`
+	NetworkMemory     int64              `json:"networkMemory"`
+	ExtendedResources map[string]float64 `json:"extendedResources"`
+}
+
+// Hardware describes the machine a task manager runs on.
+// Memory sizes are in bytes.
+type Hardware struct {
+	CPUCores       int   `json:"cpuCores"`
+	PhysicalMemory int64 `json:"physicalMemory"`
+	FreeMemory     int64 `json:"freeMemory"`
+	ManagedMemory  int64 `json:"managedMemory"`
+}
+
+// TaskManagerMemoryConfig is the memory configuration of a
+// task manager. Sizes are in bytes.
+type TaskManagerMemoryConfig struct {
+	FrameworkHeap      int64 `json:"frameworkHeap"`
+	TaskHeap           int64 `json:"taskHeap"`
+	FrameworkOffHeap   int64 `json:"frameworkOffHeap"`
+	TaskOffHeap        int64 `json:"taskOffHeap"`
+	NetworkMemory      int64 `json:"networkMemory"`
+	ManagedMemory      int64 `json:"managedMemory"`
+	JvmMetaspace       int64 `json:"jvmMetaspace"`
+	JvmOverhead        int64 `json:"jvmOverhead"`
+	TotalFlinkMemory   int64 `json:"totalFlinkMemory"`
+	TotalProcessMemory int64 `json:"totalProcessMemory"`
+}
+
+// TaskManagers returns all task managers of the cluster.
+func (c *Client) TaskManagers() (TaskManagersResp, error) {
+	return c.TaskManagersCtx(context.Background())
+}
+
+// TaskManagersCtx is like TaskManagers but uses ctx to
+// cancel the request.
+func (c *Client) TaskManagersCtx(ctx context.Context) (TaskManagersResp, error) {
+	var r TaskManagersResp
+	req, err := http.NewRequestWithContext(
+		ctx,
+		"GET",
+		c.url("/taskmanagers"),
+		nil,
+	)
+	if err != nil {
+		return r, err
+	}
+	b, err := c.client.Do(req)
+	if err != nil {
+		return r, err
+	}
+	err = json.Unmarshal(b, &r)
+	return r, err
+}
+
+type TaskManagerResp struct {
+	TaskManagerInfo
+
+	Metrics        TaskManagerMetrics `json:"metrics"`
+	AllocatedSlots []AllocatedSlot    `json:"allocatedSlots"`
+}
+
+// TaskManagerMetrics holds the JVM and network memory
+// metrics of a task manager. Sizes are in bytes.
+type TaskManagerMetrics struct {
+	HeapUsed         int64 `json:"heapUsed"`
+	HeapCommitted    int64 `json:"heapCommitted"`
+	HeapMax          int64 `json:"heapMax"`
+	NonHeapUsed      int64 `json:"nonHeapUsed"`
+	NonHeapCommitted int64 `json:"nonHeapCommitted"`
+	NonHeapMax       int64 `json:"nonHeapMax"`
+
+	DirectCount int64 `json:"directCount"`
+	DirectUsed  int64 `json:"directUsed"`
+	DirectMax   int64 `json:"directMax"`
+	MappedCount int64 `json:"mappedCount"`
+	MappedUsed  int64 `json:"mappedUsed"`
+	MappedMax   int64 `json:"mappedMax"`
+
+	NettyShuffleMemorySegmentsAvailable int64 `json:"nettyShuffleMemorySegmentsAvailable"`
+	NettyShuffleMemorySegmentsUsed      int64 `json:"nettyShuffleMemorySegmentsUsed"`
+	NettyShuffleMemorySegmentsTotal     int64 `json:"nettyShuffleMemorySegmentsTotal"`
+	NettyShuffleMemoryAvailable         int64 `json:"nettyShuffleMemoryAvailable"`
+	NettyShuffleMemoryUsed              int64 `json:"nettyShuffleMemoryUsed"`
+	NettyShuffleMemoryTotal             int64 `json:"nettyShuffleMemoryTotal"`
+
+	GarbageCollectors []GarbageCollector `json:"garbageCollectors"`
+}
+
+type GarbageCollector struct {
+	Name  string `json:"name"`
+	Count int64  `json:"count"`
+
+	// Time is the accumulated collection time in
+	// milliseconds.
+	Time int64 `json:"time"`
+}
+
+type AllocatedSlot struct {
+	JobID    string              `json:"jobId"`
+	Resource TaskManagerResource `json:"resource"`
+}
+
+// TaskManager returns details of a task manager, including
+// its memory metrics and allocated slots.
+func (c *Client) TaskManager(id string) (TaskManagerResp, error) {
+	return c.TaskManagerCtx(context.Background(), id)
+}
+
+// TaskManagerCtx is like TaskManager but uses ctx to cancel
+// the request.
+func (c *Client) TaskManagerCtx(ctx context.Context, id string) (TaskManagerResp, error) {
+	var r TaskManagerResp
+	uri := fmt.Sprintf("/taskmanagers/%s", id)
+	req, err := http.NewRequestWithContext(
+		ctx,
+		"GET",
+		c.url(uri),
+		nil,
+	)
+	if err != nil {
+		return r, err
+	}
+	b, err := c.client.Do(req)
+	if err != nil {
+		return r, err
+	}
+	err = json.Unmarshal(b, &r)
+	return r, err
+}
+
+type LogsResp struct {
+	Logs []LogFile `json:"logs"`
+}
+
+type LogFile struct {
+	Name string `json:"name"`
+
+	// Size is in bytes. MTime is the modification time in
+	// milliseconds, reported since Flink 1.16.
+	Size  int64 `json:"size"`
+	MTime int64 `json:"mtime"`
+}
+
+// ModTime returns the modification time of the log file, or
+// the zero time if it is not reported.
+func (f LogFile) ModTime() time.Time {
+	return msTime(f.MTime)
+}
+
+// TaskManagerLogs lists the log files of a task manager.
+func (c *Client) TaskManagerLogs(id string) (LogsResp, error) {
+	return c.TaskManagerLogsCtx(context.Background(), id)
+}
+
+// TaskManagerLogsCtx is like TaskManagerLogs but uses ctx to
+// cancel the request.
+func (c *Client) TaskManagerLogsCtx(ctx context.Context, id string) (LogsResp, error) {
+	var r LogsResp
+	uri := fmt.Sprintf("/taskmanagers/%s/logs", id)
+	req, err := http.NewRequestWithContext(
+		ctx,
+		"GET",
+		c.url(uri),
+		nil,
+	)
+	if err != nil {
+		return r, err
+	}
+	b, err := c.client.Do(req)
+	if err != nil {
+		return r, err
+	}
+	err = json.Unmarshal(b, &r)
+	return r, err
+}
+
+// TaskManagerLogFile returns a log file of a task manager,
+// as listed by TaskManagerLogs. The caller must close it.
+func (c *Client) TaskManagerLogFile(id string, name string) (io.ReadCloser, error) {
+	return c.TaskManagerLogFileCtx(context.Background(), id, name)
+}
+
+// TaskManagerLogFileCtx is like TaskManagerLogFile but uses
+// ctx to cancel the request and the read of the file.
+func (c *Client) TaskManagerLogFileCtx(ctx context.Context, id string, name string) (io.ReadCloser, error) {
+	uri := fmt.Sprintf("/taskmanagers/%s/logs/%s", id, url.PathEscape(name))
+	return c.stream(ctx, uri)
+}
+
+// TaskManagerLog returns the main log file of a task
+// manager. The caller must close it.
+func (c *Client) TaskManagerLog(id string) (io.ReadCloser, error) {
+	return c.TaskManagerLogCtx(context.Background(), id)
+}
+
+// TaskManagerLogCtx is like TaskManagerLog but uses ctx to
+// cancel the request and the read of the log.
+func (c *Client) TaskManagerLogCtx(ctx context.Context, id string) (io.ReadCloser, error) {
+	return c.stream(ctx, fmt.Sprintf("/taskmanagers/%s/log", id))
+}
+
+// TaskManagerStdout returns the stdout file of a task
+// manager. The caller must close it.
+func (c *Client) TaskManagerStdout(id string) (io.ReadCloser, error) {
+	return c.TaskManagerStdoutCtx(context.Background(), id)
+}
+
+// TaskManagerStdoutCtx is like TaskManagerStdout but uses
+// ctx to cancel the request and the read of the file.
+func (c *Client) TaskManagerStdoutCtx(ctx context.Context, id string) (io.ReadCloser, error) {
+	return c.stream(ctx, fmt.Sprintf("/taskmanagers/%s/stdout", id))
+}
+
+// stream sends a GET request for uri and returns the
+// response body unbuffered.
+func (c *Client) stream(ctx context.Context, uri string) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(
+		ctx,
+		"GET",
+		c.url(uri),
+		nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return c.client.Stream(req)
+}
+
+type ThreadDumpResp struct {
+	ThreadInfos []ThreadInfo `json:"threadInfos"`
+}
+
+type ThreadInfo struct {
+	ThreadName string `json:"threadName"`
+
+	// StringifiedThreadInfo is the state and stack trace of
+	// the thread as printed by the JVM.
+	StringifiedThreadInfo string `json:"stringifiedThreadInfo"`
+}
+
+// TaskManagerThreadDump returns a thread dump of a task
+// manager.
+func (c *Client) TaskManagerThreadDump(id string) (ThreadDumpResp, error) {
+	return c.TaskManagerThreadDumpCtx(context.Background(), id)
+}
+
+// TaskManagerThreadDumpCtx is like TaskManagerThreadDump but
+// uses ctx to cancel the request.
+func (c *Client) TaskManagerThreadDumpCtx(ctx context.Context, id string) (ThreadDumpResp, error) {
+	var r ThreadDumpResp
+	uri := fmt.Sprintf("/taskmanagers/%s/thread-dump", id)
+	req, err := http.NewRequestWithContext(
+		ctx,
+		"GET",
+		c.url(uri),
+		nil,
+	)
+	if err != nil {
+		return r, err
+	}
+	b, err := c.client.Do(req)
+	if err != nil {
+		return r, err
+	}
+	err = json.Unmarshal(b, &r)
+	return r, err
+}
@@ -0,0 +1,271 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+)
+
+type IOMetrics struct {
+	ReadBytes            int64 `json:"read-bytes"`
+	ReadBytesComplete    bool  `json:"read-bytes-complete"`
+	WriteBytes           int64 `json:"write-bytes"`
+	WriteBytesComplete   bool  `json:"write-bytes-complete"`
+	ReadRecords          int64 `json:"read-records"`
+	ReadRecordsComplete  bool  `json:"read-records-complete"`
+	WriteRecords         int64 `json:"write-records"`
+	WriteRecordsComplete bool  `json:"write-records-complete"`
+
+	// Accumulated times are in milliseconds. Flink reports
+	// an unknown busy time as NaN.
+	AccumulatedBackpressured int64   `json:"accumulated-backpressured-time"`
+	AccumulatedIdle          int64   `json:"accumulated-idle-time"`
+	AccumulatedBusy          float64 `json:"accumulated-busy-time"`
+}
+
+func (m *IOMetrics) UnmarshalJSON(b []byte) error {
+	type plain IOMetrics
+	aux := struct {
+		*plain
+		AccumulatedBusy interface{} `json:"accumulated-busy-time"`
+	}{plain: (*plain)(m)}
+	if err := json.Unmarshal(b, &aux); err != nil {
+		return err
+	}
+	busy, err := jsonFloat(aux.AccumulatedBusy)
+	if err != nil {
+		return fmt.Errorf("accumulated-busy-time: %w", err)
+	}
+	m.AccumulatedBusy = busy
+	return nil
+}
+
+// jsonFloat converts a decoded JSON value to a float64.
+// Flink sends NaN and infinite values as strings, and some
+// numbers as strings as well. A missing value is NaN.
+func jsonFloat(v interface{}) (float64, error) {
+	switch v := v.(type) {
+	case float64:
+		return v, nil
+	case string:
+		return strconv.ParseFloat(v, 64)
+	case nil:
+		return math.NaN(), nil
+	}
+	return 0, fmt.Errorf("unexpected number %v", v)
+}
+
+type SubtaskResp struct {
+	Subtask int    `json:"subtask"`
+	Status  string `json:"status"`
+	Attempt int    `json:"attempt"`
+
+	// Endpoint is the address of the task manager running
+	// the subtask. Flink versions before 1.17 report it as
+	// Host.
+	Endpoint      string `json:"endpoint"`
+	Host          string `json:"host"`
+	TaskManagerID string `json:"taskmanager-id"`
+
+	Start    int64 `json:"start-time"`
+	End      int64 `json:"end-time"`
+	Duration int64 `json:"duration"`
+
+	Metrics        IOMetrics        `json:"metrics"`
+	StatusDuration map[string]int64 `json:"status-duration"`
+
+	// OtherConcurrentAttempts lists speculative attempts
+	// running next to this one.
+	OtherConcurrentAttempts []SubtaskResp `json:"other-concurrent-attempts"`
+}
+
+type VertexResp struct {
+	ID             string        `json:"id"`
+	Name           string        `json:"name"`
+	Parallelism    int           `json:"parallelism"`
+	MaxParallelism int           `json:"maxParallelism"`
+	Now            int64         `json:"now"`
+	Subtasks       []SubtaskResp `json:"subtasks"`
+}
+
+// Vertex returns details of a job vertex, including the
+// current attempt of every subtask.
+func (c *Client) Vertex(jobID string, vertexID string) (VertexResp, error) {
+	return c.VertexCtx(context.Background(), jobID, vertexID)
+}
+
+// VertexCtx is like Vertex but uses ctx to cancel the
+// request.
+func (c *Client) VertexCtx(ctx context.Context, jobID string, vertexID string) (VertexResp, error) {
+	var r VertexResp
+	uri := fmt.Sprintf("/jobs/%s/vertices/%s", jobID, vertexID)
+	req, err := http.NewRequestWithContext(
+		ctx,
+		"GET",
+		c.url(uri),
+		nil,
+	)
+	if err != nil {
+		return r, err
+	}
+	b, err := c.client.Do(req)
+	if err != nil {
+		return r, err
+	}
+	err = json.Unmarshal(b, &r)
+	return r, err
+}
+
+// Subtask returns details of the current attempt of a
+// subtask.
+func (c *Client) Subtask(jobID string, vertexID string, subtask int) (SubtaskResp, error) {
+	return c.SubtaskCtx(context.Background(), jobID, vertexID, subtask)
+}
+
+// SubtaskCtx is like Subtask but uses ctx to cancel the
+// request.
+func (c *Client) SubtaskCtx(ctx context.Context, jobID string, vertexID string, subtask int) (SubtaskResp, error) {
+	var r SubtaskResp
+	uri := fmt.Sprintf("/jobs/%s/vertices/%s/subtasks/%d", jobID, vertexID, subtask)
+	req, err := http.NewRequestWithContext(
+		ctx,
+		"GET",
+		c.url(uri),
+		nil,
+	)
+	if err != nil {
+		return r, err
+	}
+	b, err := c.client.Do(req)
+	if err != nil {
+		return r, err
+	}
+	err = json.Unmarshal(b, &r)
+	return r, err
+}
+
+// SubtaskAttempt returns details of an execution attempt of
+// a subtask, e.g. one which failed before a restart.
+func (c *Client) SubtaskAttempt(jobID string, vertexID string, subtask int, attempt int) (SubtaskResp, error) {
+	return c.SubtaskAttemptCtx(context.Background(), jobID, vertexID, subtask, attempt)
+}
+
+// SubtaskAttemptCtx is like SubtaskAttempt but uses ctx to
+// cancel the request.
+func (c *Client) SubtaskAttemptCtx(ctx context.Context, jobID string, vertexID string, subtask int, attempt int) (SubtaskResp, error) {
+	var r SubtaskResp
+	uri := fmt.Sprintf("/jobs/%s/vertices/%s/subtasks/%d/attempts/%d", jobID, vertexID, subtask, attempt)
+	req, err := http.NewRequestWithContext(
+		ctx,
+		"GET",
+		c.url(uri),
+		nil,
+	)
+	if err != nil {
+		return r, err
+	}
+	b, err := c.client.Do(req)
+	if err != nil {
+		return r, err
+	}
+	err = json.Unmarshal(b, &r)
+	return r, err
+}
+
+type SubtaskTimesResp struct {
+	ID       string         `json:"id"`
+	Name     string         `json:"name"`
+	Now      int64          `json:"now"`
+	Subtasks []SubtaskTimes `json:"subtasks"`
+}
+
+type SubtaskTimes struct {
+	Subtask  int    `json:"subtask"`
+	Endpoint string `json:"endpoint"`
+	Host     string `json:"host"`
+	Duration int64  `json:"duration"`
+
+	// Timestamps maps an execution state, e.g. "RUNNING", to
+	// the time the subtask entered it.
+	Timestamps map[string]int64 `json:"timestamps"`
+}
+
+// SubtaskTimes returns the time every subtask of a vertex
+// entered each execution state.
+func (c *Client) SubtaskTimes(jobID string, vertexID string) (SubtaskTimesResp, error) {
+	return c.SubtaskTimesCtx(context.Background(), jobID, vertexID)
+}
+
+// SubtaskTimesCtx is like SubtaskTimes but uses ctx to
+// cancel the request.
+func (c *Client) SubtaskTimesCtx(ctx context.Context, jobID string, vertexID string) (SubtaskTimesResp, error) {
+	var r SubtaskTimesResp
+	uri := fmt.Sprintf("/jobs/%s/vertices/%s/subtasktimes", jobID, vertexID)
+	req, err := http.NewRequestWithContext(
+		ctx,
+		"GET",
+		c.url(uri),
+		nil,
+	)
+	if err != nil {
+		return r, err
+	}
+	b, err := c.client.Do(req)
+	if err != nil {
+		return r, err
+	}
+	err = json.Unmarshal(b, &r)
+	return r, err
+}
+
+type VertexTaskManagersResp struct {
+	ID           string              `json:"id"`
+	Name         string              `json:"name"`
+	Now          int64               `json:"now"`
+	TaskManagers []VertexTaskManager `json:"taskmanagers"`
+}
+
+type VertexTaskManager struct {
+	Endpoint      string `json:"endpoint"`
+	Host          string `json:"host"`
+	TaskManagerID string `json:"taskmanager-id"`
+	Status        string `json:"status"`
+
+	Start    int64 `json:"start-time"`
+	End      int64 `json:"end-time"`
+	Duration int64 `json:"duration"`
+
+	Metrics      IOMetrics      `json:"metrics"`
+	StatusCounts map[string]int `json:"status-counts"`
+}
+
+// VertexTaskManagers returns the subtasks of a vertex
+// grouped by the task manager they run on.
+func (c *Client) VertexTaskManagers(jobID string, vertexID string) (VertexTaskManagersResp, error) {
+	return c.VertexTaskManagersCtx(context.Background(), jobID, vertexID)
+}
+
+// VertexTaskManagersCtx is like VertexTaskManagers but uses
+// ctx to cancel the request.
+func (c *Client) VertexTaskManagersCtx(ctx context.Context, jobID string, vertexID string) (VertexTaskManagersResp, error) {
+	var r VertexTaskManagersResp
+	uri := fmt.Sprintf("/jobs/%s/vertices/%s/taskmanagers", jobID, vertexID)
+	req, err := http.NewRequestWithContext(
+		ctx,
+		"GET",
+		c.url(uri),
+		nil,
+	)
+	if err != nil {
+		return r, err
+	}
+	b, err := c.client.Do(req)
+	if err != nil {
+		return r, err
+	}
+	err = json.Unmarshal(b, &r)
+	return r, err
+}
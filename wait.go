@@ -0,0 +1,198 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type WaitOpts struct {
+	// PollInterval (optional): wait between the first two
+	// polls. It doubles after every poll up to
+	// MaxPollInterval.
+	// Defaults to 500ms.
+	PollInterval time.Duration
+
+	// MaxPollInterval (optional): longest wait between two
+	// polls. Defaults to 5s.
+	MaxPollInterval time.Duration
+
+	// Timeout (optional): gives up waiting after this
+	// duration, in addition to the deadline of the context.
+	Timeout time.Duration
+}
+
+func (o WaitOpts) withDefaults() WaitOpts {
+	if o.PollInterval <= 0 {
+		o.PollInterval = 500 * time.Millisecond
+	}
+	if o.MaxPollInterval <= 0 {
+		o.MaxPollInterval = 5 * time.Second
+	}
+	if o.MaxPollInterval < o.PollInterval {
+		o.MaxPollInterval = o.PollInterval
+	}
+	return o
+}
+
+// poll calls done until it reports true or returns an
+// error, waiting between the calls as described by opts.
+func poll(ctx context.Context, opts WaitOpts, done func(ctx context.Context) (bool, error)) error {
+	opts = opts.withDefaults()
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+	wait := opts.PollInterval
+	for {
+		ok, err := done(ctx)
+		if err != nil || ok {
+			return err
+		}
+		if err := sleep(ctx, wait); err != nil {
+			return err
+		}
+		wait *= 2
+		if wait > opts.MaxPollInterval {
+			wait = opts.MaxPollInterval
+		}
+	}
+}
+
+// OperationError is returned when an asynchronous operation
+// like a savepoint failed on the server, or did not complete
+// before the wait for it was canceled or timed out.
+type OperationError struct {
+	// Operation names the operation, e.g. "savepoint".
+	Operation string
+	TriggerID string
+
+	// Class and StackTrace describe the exception which
+	// caused the failure.
+	Class      string
+	StackTrace string
+
+	// Err is the context error if the operation did not
+	// complete in time. Class and StackTrace are empty then.
+	Err error
+}
+
+func (e *OperationError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("flink: %s %s not completed: %v", e.Operation, e.TriggerID, e.Err)
+	}
+	cause := e.Class
+	if line, _, _ := strings.Cut(strings.TrimSpace(e.StackTrace), "\n"); line != "" {
+		cause = strings.TrimSpace(line)
+	}
+	return fmt.Sprintf("flink: %s %s failed: %s", e.Operation, e.TriggerID, cause)
+}
+
+func (e *OperationError) Unwrap() error {
+	return e.Err
+}
+
+func newOperationError(operation, triggerID string, cause FailureCause) *OperationError {
+	return &OperationError{
+		Operation:  operation,
+		TriggerID:  triggerID,
+		Class:      cause.Class,
+		StackTrace: cause.StackTrace,
+	}
+}
+
+// WaitForSavepoint polls the savepoint triggered with
+// triggerID until it completes and returns its location. If
+// the savepoint failed or did not complete in time, the
+// error is an *OperationError.
+func (c *Client) WaitForSavepoint(ctx context.Context, jobID string, triggerID string, opts WaitOpts) (string, error) {
+	op := c.savepointOperation(jobID, triggerID)
+	op.WaitOpts = opts
+	r, err := op.Wait(ctx)
+	return r.Location, err
+}
+
+const (
+	JobStateFinished = "FINISHED"
+	JobStateFailed   = "FAILED"
+	JobStateCanceled = "CANCELED"
+)
+
+func isTerminalJobState(state string) bool {
+	switch state {
+	case JobStateFinished, JobStateFailed, JobStateCanceled:
+		return true
+	}
+	return false
+}
+
+type JobResult struct {
+	// Job holds the job details in its terminal state.
+	Job JobResp
+
+	ExecutionResult *JobExecutionResult
+	Accumulators    AccumulatorsResp
+
+	// RootException is the cause of the failure if the job
+	// failed.
+	RootException *FailureCause
+}
+
+// WaitForJob polls a job until it reaches a terminal state
+// (FINISHED, FAILED or CANCELED) and returns its result. A
+// failed job is not an error; check Job.State and
+// RootException. opts.Timeout bounds the whole wait,
+// including fetching the result.
+func (c *Client) WaitForJob(ctx context.Context, jobID string, opts WaitOpts) (JobResult, error) {
+	var r JobResult
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+		opts.Timeout = 0
+	}
+	err := poll(ctx, opts, func(ctx context.Context) (bool, error) {
+		job, err := c.JobCtx(ctx, jobID)
+		if err != nil {
+			return false, err
+		}
+		r.Job = job
+		return isTerminalJobState(job.State), nil
+	})
+	if err != nil {
+		return r, err
+	}
+
+	// The result is published shortly after the state
+	// changed.
+	err = poll(ctx, opts, func(ctx context.Context) (bool, error) {
+		res, err := c.ExecutionResultCtx(ctx, jobID)
+		if err != nil {
+			return false, err
+		}
+		r.ExecutionResult = res.JobExecutionResult
+		return res.Status.Id == OperationCompleted, nil
+	})
+	if err != nil {
+		return r, err
+	}
+	if r.ExecutionResult != nil {
+		r.RootException = r.ExecutionResult.FailureCause
+	}
+
+	r.Accumulators, err = c.AccumulatorsCtx(ctx, jobID, false)
+	return r, err
+}
+
+// RunJarAndWait runs a jar like RunJar and waits for the job
+// to reach a terminal state, polling as configured by wait,
+// see WaitForJob. It is meant for batch jobs.
+func (c *Client) RunJarAndWait(ctx context.Context, opts RunOpts, wait WaitOpts) (JobResult, error) {
+	run, err := c.RunJarCtx(ctx, opts)
+	if err != nil {
+		return JobResult{}, err
+	}
+	return c.WaitForJob(ctx, run.JobId, wait)
+}
@@ -0,0 +1,203 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// NoWatermark is the watermark of a subtask which has not
+// received any watermark yet.
+const NoWatermark int64 = math.MinInt64
+
+// MaxWatermark is the watermark of a subtask whose input
+// ended, e.g. because a bounded source finished.
+const MaxWatermark int64 = math.MaxInt64
+
+type SubtaskWatermark struct {
+	Subtask int
+
+	// Watermark is the current input watermark in
+	// milliseconds since the epoch, NoWatermark or
+	// MaxWatermark.
+	Watermark int64
+}
+
+// Time returns the watermark as a time.Time. It returns the
+// zero time for NoWatermark and MaxWatermark.
+func (w SubtaskWatermark) Time() time.Time {
+	if w.Watermark == NoWatermark || w.Watermark == MaxWatermark {
+		return time.Time{}
+	}
+	return time.UnixMilli(w.Watermark)
+}
+
+const currentInputWatermark = "currentInputWatermark"
+
+// Watermarks returns the current input watermark of every
+// subtask of a vertex.
+func (c *Client) Watermarks(jobID string, vertexID string) ([]SubtaskWatermark, error) {
+	return c.WatermarksCtx(context.Background(), jobID, vertexID)
+}
+
+// WatermarksCtx is like Watermarks but uses ctx to cancel
+// the request.
+func (c *Client) WatermarksCtx(ctx context.Context, jobID string, vertexID string) ([]SubtaskWatermark, error) {
+	var r []SubtaskWatermark
+	uri := fmt.Sprintf("/jobs/%s/vertices/%s/watermarks", jobID, vertexID)
+	req, err := http.NewRequestWithContext(
+		ctx,
+		"GET",
+		c.url(uri),
+		nil,
+	)
+	if err != nil {
+		return r, err
+	}
+	b, err := c.client.Do(req)
+	if err != nil {
+		return r, err
+	}
+	var metrics []struct {
+		ID    string `json:"id"`
+		Value string `json:"value"`
+	}
+	if err := json.Unmarshal(b, &metrics); err != nil {
+		return r, err
+	}
+	// Metric IDs look like "<subtask>.currentInputWatermark".
+	for _, m := range metrics {
+		index, name, ok := strings.Cut(m.ID, ".")
+		if !ok || name != currentInputWatermark {
+			continue
+		}
+		subtask, err := strconv.Atoi(index)
+		if err != nil {
+			return r, fmt.Errorf("watermark metric %q: %w", m.ID, err)
+		}
+		watermark, err := strconv.ParseInt(m.Value, 10, 64)
+		if err != nil {
+			return r, fmt.Errorf("watermark metric %q: %w", m.ID, err)
+		}
+		r = append(r, SubtaskWatermark{Subtask: subtask, Watermark: watermark})
+	}
+	return r, nil
+}
+
+type WatermarkLagOpts struct {
+	// MaxLag (optional): subtasks whose watermark is further
+	// behind wall-clock time are reported as lagging.
+	// Defaults to 1 minute.
+	MaxLag time.Duration
+
+	// Now (optional): the wall-clock time lags are computed
+	// against. Defaults to the current time.
+	Now time.Time
+
+	// MinIdleRatio (optional): subtasks which were idle, i.e.
+	// waited for input, for at least this fraction of time
+	// are reported as idle. Defaults to 0.95.
+	MinIdleRatio float64
+}
+
+type WatermarkLag struct {
+	VertexID   string
+	VertexName string
+	SubtaskWatermark
+
+	// Lag is how far the watermark is behind wall-clock
+	// time. It is zero for pending and finished subtasks and
+	// for watermarks ahead of wall-clock time.
+	Lag time.Duration
+
+	// Pending is set when the subtask has not received any
+	// watermark yet.
+	Pending bool
+
+	// Finished is set when the input of the subtask ended.
+	Finished bool
+
+	// IdleRatio is the fraction of time the subtask waited
+	// for input, as reported by Backpressure. Idle is set
+	// when it reaches MinIdleRatio. Both are zero if Flink
+	// has no backpressure statistics for the vertex.
+	IdleRatio float64
+	Idle      bool
+
+	// Lagging is set when Lag exceeds MaxLag and the subtask
+	// is not idle, since the watermark of an idle subtask
+	// is expected to fall behind.
+	Lagging bool
+}
+
+// WatermarkLags computes the watermark lag of every subtask
+// of a job, in plan order. Sources are skipped since they
+// have no input watermark. Idle subtasks are detected from
+// the backpressure statistics of each vertex.
+func (c *Client) WatermarkLags(ctx context.Context, jobID string, opts WatermarkLagOpts) ([]WatermarkLag, error) {
+	if opts.MaxLag <= 0 {
+		opts.MaxLag = time.Minute
+	}
+	if opts.Now.IsZero() {
+		opts.Now = time.Now()
+	}
+	if opts.MinIdleRatio <= 0 {
+		opts.MinIdleRatio = 0.95
+	}
+	job, err := c.JobCtx(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+	hasInputs := map[string]bool{}
+	for _, node := range job.Plan.Nodes {
+		hasInputs[node.ID] = len(node.Inputs) > 0
+	}
+
+	var lags []WatermarkLag
+	for _, v := range job.Vertices {
+		if !hasInputs[v.ID] {
+			continue
+		}
+		watermarks, err := c.WatermarksCtx(ctx, jobID, v.ID)
+		if err != nil {
+			return nil, err
+		}
+		bp, err := c.BackpressureCtx(ctx, jobID, v.ID)
+		if err != nil {
+			return nil, err
+		}
+		idleRatios := map[int]float64{}
+		if bp.Status == BackpressureStatusOK {
+			for _, st := range bp.Subtasks {
+				idleRatios[st.Subtask] = st.IdleRatio
+			}
+		}
+		for _, w := range watermarks {
+			lag := WatermarkLag{
+				VertexID:         v.ID,
+				VertexName:       v.Name,
+				SubtaskWatermark: w,
+				IdleRatio:        idleRatios[w.Subtask],
+			}
+			lag.Idle = lag.IdleRatio >= opts.MinIdleRatio
+			switch w.Watermark {
+			case NoWatermark:
+				lag.Pending = true
+			case MaxWatermark:
+				lag.Finished = true
+			default:
+				if d := opts.Now.Sub(w.Time()); d > 0 {
+					lag.Lag = d
+				}
+				lag.Lagging = lag.Lag > opts.MaxLag && !lag.Idle
+			}
+			lags = append(lags, lag)
+		}
+	}
+	return lags, nil
+}
@@ -0,0 +1,107 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestWatermarkLags(t *testing.T) {
+	now := time.UnixMilli(1700000000000)
+	ms := func(d time.Duration) int64 { return now.Add(d).UnixMilli() }
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/jobs/j", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{
+			"jid": "j",
+			"vertices": [
+				{"id": "src", "name": "Source"},
+				{"id": "map", "name": "Map"},
+				{"id": "sink", "name": "Sink"}
+			],
+			"plan": {"nodes": [
+				{"id": "src"},
+				{"id": "map", "inputs": [{"id": "src"}]},
+				{"id": "sink", "inputs": [{"id": "map"}]}
+			]}
+		}`))
+	})
+	mux.HandleFunc("/jobs/j/vertices/map/watermarks", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `[
+			{"id": "0.currentInputWatermark", "value": "%d"},
+			{"id": "0.numRecordsIn", "value": "12"},
+			{"id": "1.currentInputWatermark", "value": "%d"},
+			{"id": "2.currentInputWatermark", "value": "-9223372036854775808"},
+			{"id": "3.currentInputWatermark", "value": "9223372036854775807"},
+			{"id": "4.currentInputWatermark", "value": "%d"},
+			{"id": "5.currentInputWatermark", "value": "%d"}
+		]`, ms(-10*time.Second), ms(-5*time.Minute), ms(time.Minute), ms(-5*time.Minute))
+	})
+	mux.HandleFunc("/jobs/j/vertices/map/backpressure", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"status": "ok", "subtasks": [
+			{"subtask": 0, "idleRatio": 0.2},
+			{"subtask": 1, "idleRatio": 0.1},
+			{"subtask": 5, "idleRatio": 1.0}
+		]}`))
+	})
+	mux.HandleFunc("/jobs/j/vertices/sink/watermarks", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `[{"id": "0.currentInputWatermark", "value": "%d"}]`, ms(-2*time.Minute))
+	})
+	mux.HandleFunc("/jobs/j/vertices/sink/backpressure", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"status": "deprecated"}`))
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request %s", r.URL.Path)
+		http.NotFound(w, r)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+	c, err := New(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lags, err := c.WatermarkLags(context.Background(), "j", WatermarkLagOpts{Now: now})
+	if err != nil {
+		t.Fatal(err)
+	}
+	lag := func(vertex string, subtask int, watermark int64) WatermarkLag {
+		name := map[string]string{"map": "Map", "sink": "Sink"}[vertex]
+		return WatermarkLag{
+			VertexID:         vertex,
+			VertexName:       name,
+			SubtaskWatermark: SubtaskWatermark{Subtask: subtask, Watermark: watermark},
+		}
+	}
+	want := []WatermarkLag{
+		lag("map", 0, ms(-10*time.Second)),
+		lag("map", 1, ms(-5*time.Minute)),
+		lag("map", 2, NoWatermark),
+		lag("map", 3, MaxWatermark),
+		// A watermark ahead of wall-clock time has no lag.
+		lag("map", 4, ms(time.Minute)),
+		lag("map", 5, ms(-5*time.Minute)),
+		// Without backpressure statistics, idleness is unknown.
+		lag("sink", 0, ms(-2*time.Minute)),
+	}
+	want[0].Lag, want[0].IdleRatio = 10*time.Second, 0.2
+	want[1].Lag, want[1].IdleRatio, want[1].Lagging = 5*time.Minute, 0.1, true
+	want[2].Pending = true
+	want[3].Finished = true
+	want[5].Lag, want[5].IdleRatio, want[5].Idle = 5*time.Minute, 1.0, true
+	want[6].Lag, want[6].Lagging = 2*time.Minute, true
+	if !reflect.DeepEqual(lags, want) {
+		t.Errorf("WatermarkLags =\n%+v\nwant\n%+v", lags, want)
+	}
+
+	if !want[2].Time().IsZero() || !want[3].Time().IsZero() {
+		t.Error("Time of NoWatermark or MaxWatermark is not the zero time")
+	}
+	if got := want[0].Time(); !got.Equal(now.Add(-10 * time.Second)) {
+		t.Errorf("Time = %s, want %s", got, now.Add(-10*time.Second))
+	}
+}